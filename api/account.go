@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** accountResponse is an account as the API shows it: the balance carries its currency
+type accountResponse struct {
+	ID             int64            `json:"id"`
+	Owner          string           `json:"owner"`
+	Balance        money.Money      `json:"balance"`
+	OverdraftLimit money.Money      `json:"overdraft_limit"`
+	Status         db.AccountStatus `json:"status"`
+	Version        int64            `json:"version"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		ID:             account.ID,
+		Owner:          account.Owner,
+		Balance:        account.BalanceMoney(),
+		OverdraftLimit: account.OverdraftLimitMoney(),
+		Status:         account.Status,
+		Version:        account.Version,
+		CreatedAt:      account.CreatedAt,
+	}
+}
+
+type createAccountRequest struct {
+	Currency string `json:"currency" binding:"required,currency"`
+}
+
+func (server *Server) createAccount(ctx *gin.Context) {
+	var req createAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !server.checkCurrency(ctx, req.Currency) {
+		return
+	}
+
+	authPayload := authPayload(ctx)
+	account, err := server.store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    authPayload.Username,
+		Balance:  0,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	setAccountETag(ctx, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type getAccountRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.validAccountViewer(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	setAccountETag(ctx, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type updateAccountStatusRequest struct {
+	Status db.AccountStatus `json:"status" binding:"required,oneof=active frozen closed"`
+}
+
+// ** updateAccountStatus freezes, unfreezes or closes an account. Only bankers and admins may.
+// ** Send the ETag of the account in If-Match to make sure nobody changed it in the meantime.
+func (server *Server) updateAccountStatus(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req updateAccountStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	expectedVersion, err := ifMatchVersion(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.ChangeAccountStatus(ctx, uri.ID, req.Status, expectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrConcurrentModification):
+			ctx.JSON(http.StatusPreconditionFailed, errorResponse(err))
+		case errors.Is(err, db.ErrInvalidStatusTransition), errors.Is(err, db.ErrNonZeroBalance):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	setAccountETag(ctx, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type listAccountsRequest struct {
+	Owner    string `form:"owner"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// ** listAccounts lists the accounts of the authenticated user.
+// ** Bankers and admins may list the accounts of another user with ?owner=.
+func (server *Server) listAccounts(ctx *gin.Context) {
+	var req listAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := authPayload(ctx)
+	owner := authPayload.Username
+	if req.Owner != "" && req.Owner != owner {
+		if !hasRole(authPayload, staffRoles...) {
+			err := errors.New("only bankers and admins can list accounts of other users")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		owner = req.Owner
+	}
+
+	accounts, err := server.store.ListAccountsByOwner(ctx, db.ListAccountsByOwnerParams{
+		Owner:  owner,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]accountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		rsp = append(rsp, newAccountResponse(account))
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// ** validAccountOwner loads an account and checks that it belongs to the authenticated user.
+// ** It writes the error response and returns false otherwise.
+// ** The account is about to be debited, so it is read from the primary: an account opened
+// **  a moment ago may not have reached the read replica yet.
+func (server *Server) validAccountOwner(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	return server.validAccount(ctx, db.ForcePrimary(ctx), accountID, false)
+}
+
+// ** validAccountViewer is like validAccountOwner, but bankers and admins may see every account
+func (server *Server) validAccountViewer(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	return server.validAccount(ctx, ctx, accountID, true)
+}
+
+func (server *Server) validAccount(ctx *gin.Context, storeCtx context.Context, accountID int64, staffAllowed bool) (db.Account, bool) {
+	account, err := server.store.GetAccount(storeCtx, accountID)
+	if err != nil {
+		if err == db.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+
+	authPayload := authPayload(ctx)
+	if staffAllowed && hasRole(authPayload, staffRoles...) {
+		return account, true
+	}
+	if account.Owner != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return account, false
+	}
+	return account, true
+}
+
+type adjustAccountBalanceRequest struct {
+	Amount money.Money `json:"amount"`
+}
+
+type adjustAccountBalanceResponse struct {
+	Account accountResponse `json:"account"`
+	EntryID int64           `json:"entry_id"`
+}
+
+// ** adjustAccountBalance books a manual correction of a signed amount on an account. Only admins may.
+func (server *Server) adjustAccountBalance(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req adjustAccountBalanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Amount.IsZero() {
+		err := fmt.Errorf("%w: adjustment must not be zero", money.ErrInvalidAmount)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{
+		AccountID: uri.ID,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	setAccountETag(ctx, result.Account)
+	ctx.JSON(http.StatusOK, adjustAccountBalanceResponse{
+		Account: newAccountResponse(result.Account),
+		EntryID: result.Entry.ID,
+	})
+}
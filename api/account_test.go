@@ -0,0 +1,183 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+func TestCreateAccountRejectsUnknownCurrency(t *testing.T) {
+	server := newTestServer(t, nil)
+	for _, currency := range []string{"", "XYZ", "eur"} {
+		body, err := json.Marshal(gin.H{"currency": currency})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(body))
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", db.UserRoleCustomer, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, currency)
+	}
+}
+
+func TestAccountResponseJSON(t *testing.T) {
+	account := db.Account{
+		ID:             7,
+		Owner:          "alice",
+		Balance:        -1234,
+		OverdraftLimit: 5000,
+		Currency:       "EUR",
+		Status:         db.AccountStatusActive,
+		Version:        3,
+		CreatedAt:      time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	data, err := json.Marshal(newAccountResponse(account))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": 7,
+		"owner": "alice",
+		"balance": {"amount": "-12.34", "currency": "EUR"},
+		"overdraft_limit": {"amount": "50.00", "currency": "EUR"},
+		"status": "active",
+		"version": 3,
+		"created_at": "2023-03-01T00:00:00Z"
+	}`, string(data))
+}
+
+func randomAccount(owner string) db.Account {
+	return db.Account{
+		ID:        testRand.Int(1, 1000),
+		Owner:     owner,
+		Balance:   testRand.Money(),
+		Currency:  testRand.Currency(),
+		Status:    db.AccountStatusActive,
+		Version:   1,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Account) {
+	var got accountResponse
+	require.NoError(t, json.Unmarshal(body.Bytes(), &got))
+	require.Equal(t, newAccountResponse(account), got)
+}
+
+func TestGetAccountAPI(t *testing.T) {
+	account := randomAccount("alice")
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		username      string
+		role          db.UserRole
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			username:  account.Owner,
+			role:      db.UserRoleCustomer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, accountETag(account), recorder.Header().Get("ETag"))
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:      "OtherOwner",
+			accountID: account.ID,
+			username:  "mallory",
+			role:      db.UserRoleCustomer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "Banker",
+			accountID: account.ID,
+			username:  "carol",
+			role:      db.UserRoleBanker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:      "NotFound",
+			accountID: account.ID,
+			username:  account.Owner,
+			role:      db.UserRoleCustomer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "InternalError",
+			accountID: account.ID,
+			username:  account.Owner,
+			role:      db.UserRoleCustomer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidID",
+			accountID: 0,
+			username:  account.Owner,
+			role:      db.UserRoleCustomer,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", tc.accountID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
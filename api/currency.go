@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+type currencyResponse struct {
+	Code     string `json:"code"`
+	Exponent int32  `json:"exponent"`
+}
+
+// ** listCurrencies returns the currencies accounts can be opened in
+func (server *Server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListEnabledCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]currencyResponse, 0, len(currencies))
+	for _, currency := range currencies {
+		rsp = append(rsp, currencyResponse{Code: currency.Code, Exponent: currency.Exponent})
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// ** checkCurrency checks the currency against the currencies table.
+// ** It writes a 400 response and returns false for unknown or disabled currencies.
+func (server *Server) checkCurrency(ctx *gin.Context, code string) bool {
+	_, err := server.store.EnabledCurrency(ctx, code)
+	if err != nil {
+		if errors.Is(err, db.ErrUnsupportedCurrency) || errors.Is(err, db.ErrCurrencyDisabled) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+type updateCurrencyURI struct {
+	Code string `uri:"code" binding:"required,len=3,uppercase"`
+}
+
+type updateCurrencyRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type currencyAdminResponse struct {
+	Code     string `json:"code"`
+	Exponent int32  `json:"exponent"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// ** updateCurrency enables or disables a currency for new accounts. Only admins may.
+func (server *Server) updateCurrency(ctx *gin.Context) {
+	var uri updateCurrencyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var req updateCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	currency, err := server.store.SetCurrencyEnabled(ctx, db.SetCurrencyEnabledParams{
+		Code:    uri.Code,
+		Enabled: *req.Enabled,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, currencyAdminResponse{
+		Code:     currency.Code,
+		Exponent: currency.Exponent,
+		Enabled:  currency.Enabled,
+	})
+}
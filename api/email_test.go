@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** these requests are rejected before the store is used, so the server needs no database
+func TestEmailFlowValidation(t *testing.T) {
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"VerifyEmailMissingCode", http.MethodGet, "/users/verify_email?email_id=1", ""},
+		{"VerifyEmailInvalidID", http.MethodGet, "/users/verify_email?email_id=0&secret_code=abc", ""},
+		{"PasswordResetInvalidEmail", http.MethodPost, "/users/password_reset", `{"email":"alice"}`},
+		{"ResetPasswordMissingCode", http.MethodPost, "/users/password_reset/confirm", `{"reset_id":1,"password":"secret"}`},
+		{"ResetPasswordTooShort", http.MethodPost, "/users/password_reset/confirm", `{"reset_id":1,"secret_code":"abc","password":"123"}`},
+	}
+
+	server := newTestServer(t, nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+var errInvalidIfMatch = errors.New("If-Match must be a single strong entity tag such as \"3\"")
+
+// ** accountETag is the entity tag of an account: its version as a strong tag
+func accountETag(account db.Account) string {
+	return strconv.Quote(strconv.FormatInt(account.Version, 10))
+}
+
+// ** setAccountETag lets clients send the version back in If-Match
+func setAccountETag(ctx *gin.Context, account db.Account) {
+	ctx.Header("ETag", accountETag(account))
+}
+
+// ** ifMatchVersion reads the expected account version from the If-Match header.
+// ** It returns 0 when the header is missing or "*", meaning any version.
+func ifMatchVersion(ctx *gin.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w, got %s", errInvalidIfMatch, header)
+	}
+	return version, nil
+}
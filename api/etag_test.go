@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+func TestAccountETag(t *testing.T) {
+	require.Equal(t, `"7"`, accountETag(db.Account{Version: 7}))
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	testCases := []struct {
+		header  string
+		version int64
+		valid   bool
+	}{
+		{"", 0, true},
+		{"*", 0, true},
+		{`"7"`, 7, true},
+		{` "12" `, 12, true},
+		{"7", 0, false},
+		{`W/"7"`, 0, false},
+		{`"0"`, 0, false},
+		{`"abc"`, 0, false},
+		{`"1", "2"`, 0, false},
+	}
+
+	for _, tc := range testCases {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodPatch, "/accounts/1/status", nil)
+		if tc.header != "" {
+			ctx.Request.Header.Set("If-Match", tc.header)
+		}
+
+		version, err := ifMatchVersion(ctx)
+		if !tc.valid {
+			require.ErrorIs(t, err, errInvalidIfMatch, tc.header)
+			continue
+		}
+		require.NoError(t, err, tc.header)
+		require.Equal(t, tc.version, version, tc.header)
+	}
+}
+
+func TestUpdateAccountStatusRejectsInvalidIfMatch(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPatch, "/accounts/1/status", bytes.NewBufferString(`{"status":"frozen"}`))
+	require.NoError(t, err)
+	request.Header.Set("If-Match", `W/"1"`)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", db.UserRoleBanker, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
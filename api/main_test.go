@@ -0,0 +1,57 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/worker"
+)
+
+var seed = util.SeedFlag()
+
+var testRand *util.Rand
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:    testRand.String(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+	}
+
+	server, err := NewServer(config, store, worker.NewPGTaskDistributor(store))
+	require.NoError(t, err)
+
+	return server
+}
+
+func addAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.Maker,
+	authorizationType string,
+	username string,
+	role db.UserRole,
+	duration time.Duration,
+) {
+	token, payload, err := tokenMaker.CreateToken(username, string(role), duration, token.TokenTypeAccess)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
+	request.Header.Set(authorizationHeaderKey, authorizationHeader)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(util.RunWithSeed(m, seed, func(r *util.Rand) {
+		testRand = r
+		gin.SetMode(gin.TestMode)
+	}))
+}
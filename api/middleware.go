@@ -0,0 +1,111 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/token"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// ** requestIDMiddleware tags every request with an ID, taken from the client or generated,
+// **  and passes it to the store so the audit log can tie mutations to requests
+func requestIDMiddleware(ctx *gin.Context) {
+	requestID := ctx.GetHeader(requestIDHeader)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = newRequestID()
+	}
+
+	ctx.Header(requestIDHeader, requestID)
+	ctx.Request = ctx.Request.WithContext(db.WithRequestID(ctx.Request.Context(), requestID))
+	ctx.Next()
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+)
+
+// ** authMiddleware requires a valid access token and records its user as the actor of store mutations
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			err := errors.New("authorization header is not provided")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) < 2 {
+			err := errors.New("invalid authorization header format")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		accessToken := fields[1]
+		payload, err := tokenMaker.VerifyToken(accessToken, token.TokenTypeAccess)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Request = ctx.Request.WithContext(db.WithAuditActor(ctx.Request.Context(), payload.Username))
+		ctx.Next()
+	}
+}
+
+// ** authPayload returns the payload of the access token checked by authMiddleware
+func authPayload(ctx *gin.Context) *token.Payload {
+	return ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+}
+
+// ** requireRole lets a request through only if its access token carries one of the roles.
+// ** It must run after authMiddleware.
+func requireRole(roles ...db.UserRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !hasRole(authPayload(ctx), roles...) {
+			err := fmt.Errorf("role %q is not allowed to access this resource", authPayload(ctx).Role)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// ** hasRole tells whether the token payload carries one of the roles
+func hasRole(payload *token.Payload, roles ...db.UserRole) bool {
+	for _, role := range roles {
+		if payload.Role == string(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// ** staffRoles may look at the accounts of every user and freeze them
+var staffRoles = []db.UserRole{db.UserRoleBanker, db.UserRoleAdmin}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/token"
+)
+
+// ** allowed roles send an invalid request, so they get a 400 from validation instead of reaching
+// **  the store; forbidden roles must be turned away with a 403 before that
+func TestRoleAccess(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		codes  map[db.UserRole]int
+	}{
+		{
+			name:   "UpdateAccountStatus",
+			method: http.MethodPatch,
+			path:   "/accounts/1/status",
+			body:   `{"status":"bogus"}`,
+			codes: map[db.UserRole]int{
+				db.UserRoleCustomer: http.StatusForbidden,
+				db.UserRoleBanker:   http.StatusBadRequest,
+				db.UserRoleAdmin:    http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "AdjustAccountBalance",
+			method: http.MethodPost,
+			path:   "/accounts/1/adjustments",
+			body:   `{"amount":{"amount":"0","currency":"EUR"}}`,
+			codes: map[db.UserRole]int{
+				db.UserRoleCustomer: http.StatusForbidden,
+				db.UserRoleBanker:   http.StatusForbidden,
+				db.UserRoleAdmin:    http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "UpdateCurrency",
+			method: http.MethodPatch,
+			path:   "/currencies/EUR",
+			body:   `{}`,
+			codes: map[db.UserRole]int{
+				db.UserRoleCustomer: http.StatusForbidden,
+				db.UserRoleBanker:   http.StatusForbidden,
+				db.UserRoleAdmin:    http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "UpdateUserRole",
+			method: http.MethodPatch,
+			path:   "/users/bob/role",
+			body:   `{"role":"root"}`,
+			codes: map[db.UserRole]int{
+				db.UserRoleCustomer: http.StatusForbidden,
+				db.UserRoleBanker:   http.StatusForbidden,
+				db.UserRoleAdmin:    http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "ListAccountsOfAnotherUser",
+			method: http.MethodGet,
+			path:   "/accounts?owner=bob&page_id=1&page_size=5",
+			codes: map[db.UserRole]int{
+				db.UserRoleCustomer: http.StatusForbidden,
+			},
+		},
+		{
+			name:   "ListAccountsOfAnotherUserInvalidPage",
+			method: http.MethodGet,
+			path:   "/accounts?owner=bob&page_id=1&page_size=1",
+			codes: map[db.UserRole]int{
+				db.UserRoleCustomer: http.StatusBadRequest,
+				db.UserRoleBanker:   http.StatusBadRequest,
+				db.UserRoleAdmin:    http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		for role, code := range tc.codes {
+			t.Run(tc.name+"/"+string(role), func(t *testing.T) {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+				require.NoError(t, err)
+
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", role, time.Minute)
+				server.router.ServeHTTP(recorder, request)
+				require.Equal(t, code, recorder.Code)
+			})
+		}
+	}
+}
+
+// ** the role change and the revocation of the sessions are one transaction
+func TestUpdateUserRoleRevokesSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	store.EXPECT().UpdateUserRoleTx(gomock.Any(), gomock.Eq(db.UpdateUserRoleParams{
+		Username: "bob",
+		Role:     db.UserRoleBanker,
+	})).Times(1).Return(db.UpdateUserRoleTxResult{
+		User:            db.User{Username: "bob", Role: db.UserRoleBanker},
+		RevokedSessions: 2,
+	}, nil)
+	store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().BlockUserSessions(gomock.Any(), gomock.Any()).Times(0)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPatch, "/users/bob/role", bytes.NewBufferString(`{"role":"banker"}`))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", db.UserRoleAdmin, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRoleAccessWithoutRole(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPatch, "/accounts/1/status", bytes.NewBufferString(`{"status":"frozen"}`))
+	require.NoError(t, err)
+
+	// ** tokens issued before roles existed carry none and get no staff rights
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", "", time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestHasRole(t *testing.T) {
+	payload := &token.Payload{Username: "alice", Role: string(db.UserRoleBanker)}
+
+	require.True(t, hasRole(payload, staffRoles...))
+	require.True(t, hasRole(payload, db.UserRoleBanker))
+	require.False(t, hasRole(payload, db.UserRoleAdmin))
+	require.False(t, hasRole(payload))
+}
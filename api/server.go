@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/token"
+	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/worker"
+)
+
+// ** Server serves HTTP requests for our banking service
+type Server struct {
+	config          util.Config
+	store           db.Store
+	tokenMaker      token.Maker
+	taskDistributor worker.TaskDistributor
+	router          *gin.Engine
+}
+
+// ** NewServer creates a new HTTP server and sets up routing
+func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error) {
+	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	server := &Server{
+		config:          config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		taskDistributor: taskDistributor,
+	}
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("currency", validCurrency)
+	}
+
+	server.setupRouter()
+	return server, nil
+}
+
+func (server *Server) setupRouter() {
+	router := gin.Default()
+	// ** handlers pass the gin context to the store, which reads the request ID from the request context
+	router.ContextWithFallback = true
+	router.Use(requestIDMiddleware)
+
+	router.POST("/users", server.createUser)
+	router.POST("/users/login", server.loginUser)
+	router.GET("/users/verify_email", server.verifyEmail)
+	router.POST("/users/password_reset", server.requestPasswordReset)
+	router.POST("/users/password_reset/confirm", server.resetPassword)
+	router.POST("/tokens/renew_access", server.renewAccessToken)
+
+	router.GET("/currencies", server.listCurrencies)
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+
+	authRoutes.GET("/users/sessions", server.listSessions)
+	authRoutes.DELETE("/users/sessions", server.revokeSessions)
+	authRoutes.DELETE("/users/sessions/:id", server.revokeSession)
+
+	authRoutes.POST("/accounts", server.createAccount)
+	authRoutes.GET("/accounts/:id", server.getAccount)
+	authRoutes.GET("/accounts", server.listAccounts)
+
+	authRoutes.POST("/transfers", server.createTransfer)
+
+	staffRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), requireRole(staffRoles...))
+
+	staffRoutes.PATCH("/accounts/:id/status", server.updateAccountStatus)
+
+	adminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), requireRole(db.UserRoleAdmin))
+
+	adminRoutes.POST("/accounts/:id/adjustments", server.adjustAccountBalance)
+	adminRoutes.PATCH("/currencies/:code", server.updateCurrency)
+	adminRoutes.PATCH("/users/:username/role", server.updateUserRole)
+
+	server.router = router
+}
+
+// ** Start runs the HTTP server on a specific address
+func (server *Server) Start(address string) error {
+	return server.router.Run(address)
+}
+
+func errorResponse(err error) gin.H {
+	return gin.H{"error": err.Error()}
+}
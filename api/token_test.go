@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/token"
+)
+
+// ** refresh tokens are checked before the session is loaded, so these need no database
+func TestRenewAccessTokenRejectsInvalidRefreshToken(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	otherServer := newTestServer(t, nil)
+	foreignToken, _, err := otherServer.tokenMaker.CreateToken("alice", "customer", time.Hour, token.TokenTypeRefresh)
+	require.NoError(t, err)
+	expiredToken, _, err := server.tokenMaker.CreateToken("alice", "customer", -time.Minute, token.TokenTypeRefresh)
+	require.NoError(t, err)
+	accessToken, _, err := server.tokenMaker.CreateToken("alice", "customer", time.Hour, token.TokenTypeAccess)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		body         gin.H
+		expectedCode int
+	}{
+		{"Missing", gin.H{}, http.StatusBadRequest},
+		{"Garbage", gin.H{"refresh_token": "abc"}, http.StatusUnauthorized},
+		{"SignedByAnotherKey", gin.H{"refresh_token": foreignToken}, http.StatusUnauthorized},
+		{"Expired", gin.H{"refresh_token": expiredToken}, http.StatusUnauthorized},
+		{"AccessToken", gin.H{"refresh_token": accessToken}, http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+// ** the role of the new access token is the user's current one, not the one in the refresh token
+func TestRenewAccessTokenReadsRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken("alice", string(db.UserRoleAdmin), time.Hour, token.TokenTypeRefresh)
+	require.NoError(t, err)
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(refreshPayload.ID)).Times(1).Return(db.Session{
+		ID:           refreshPayload.ID,
+		Username:     "alice",
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	}, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("alice")).Times(1).Return(db.User{
+		Username: "alice",
+		Role:     db.UserRoleCustomer,
+	}, nil)
+
+	data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp renewAccessTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	accessPayload, err := server.tokenMaker.VerifyToken(rsp.AccessToken, token.TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, string(db.UserRoleCustomer), accessPayload.Role)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+type transferRequest struct {
+	FromAccountID int64       `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64       `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
+	Amount        money.Money `json:"amount"`
+}
+
+// ** transferResponse reports the transfer and both balances with their currency
+type transferResponse struct {
+	TransferID  int64           `json:"transfer_id"`
+	Amount      money.Money     `json:"amount"`
+	Fee         money.Money     `json:"fee"`
+	FromAccount accountResponse `json:"from_account"`
+	ToAccount   accountResponse `json:"to_account"`
+}
+
+func (server *Server) createTransfer(ctx *gin.Context) {
+	var req transferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg, err := db.NewTransferTxParams(req.FromAccountID, req.ToAccountID, req.Amount)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !server.checkCurrency(ctx, arg.Currency) {
+		return
+	}
+	if !server.checkEmailVerified(ctx) {
+		return
+	}
+	if _, ok := server.validAccountOwner(ctx, arg.FromAccountID); !ok {
+		return
+	}
+
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(transferErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferResponse{
+		TransferID:  result.Transfer.ID,
+		Amount:      req.Amount,
+		Fee:         money.Money{Amount: result.Fee, Currency: req.Amount.Currency},
+		FromAccount: newAccountResponse(result.FromAccount),
+		ToAccount:   newAccountResponse(result.ToAccount),
+	})
+}
+
+// ** transferErrorStatus maps the errors of TransferTx to an HTTP status
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound), errors.Is(err, db.ErrForeignKeyViolation):
+		return http.StatusNotFound
+	case errors.Is(err, money.ErrCurrencyMismatch), errors.Is(err, db.ErrCheckViolation):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrAccountFrozen), errors.Is(err, db.ErrAccountClosed):
+		return http.StatusForbidden
+	case errors.Is(err, db.ErrLimitExceeded), errors.Is(err, db.ErrInsufficientFunds):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
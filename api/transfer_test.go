@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/techschool/simplebank/db/mock"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** these requests are rejected before the store is used, so the server needs no database
+func TestCreateTransferValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{"UnknownCurrency", `{"from_account_id":1,"to_account_id":2,"amount":{"amount":"1.00","currency":"XYZ"}}`},
+		{"TooManyDecimals", `{"from_account_id":1,"to_account_id":2,"amount":{"amount":"1.005","currency":"EUR"}}`},
+		{"NumericAmount", `{"from_account_id":1,"to_account_id":2,"amount":{"amount":1.5,"currency":"EUR"}}`},
+		{"ZeroAmount", `{"from_account_id":1,"to_account_id":2,"amount":{"amount":"0","currency":"EUR"}}`},
+		{"NegativeAmount", `{"from_account_id":1,"to_account_id":2,"amount":{"amount":"-5.00","currency":"EUR"}}`},
+		{"MissingAmount", `{"from_account_id":1,"to_account_id":2}`},
+		{"SameAccount", `{"from_account_id":1,"to_account_id":1,"amount":{"amount":"1.00","currency":"EUR"}}`},
+	}
+
+	server := newTestServer(t, nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "alice", db.UserRoleCustomer, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+		})
+	}
+}
+
+func TestCreateTransferAPI(t *testing.T) {
+	account1 := randomAccount("alice")
+	account1.Currency = "EUR"
+	account2 := randomAccount("bob")
+	account2.ID = account1.ID + 1
+	account2.Currency = "EUR"
+	user := db.User{Username: account1.Owner, Role: db.UserRoleCustomer, IsEmailVerified: true}
+
+	body := fmt.Sprintf(`{"from_account_id":%d,"to_account_id":%d,"amount":{"amount":"1.50","currency":"EUR"}}`, account1.ID, account2.ID)
+	arg := db.TransferTxParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 150, Currency: "EUR"}
+
+	// ** every case passes the currency, email and ownership checks unless it stubs them otherwise
+	buildChecks := func(store *mockdb.MockStore) {
+		store.EXPECT().EnabledCurrency(gomock.Any(), gomock.Eq("EUR")).Times(1).Return(db.Currency{Code: "EUR", Exponent: 2, Enabled: true}, nil)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		status     int
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{
+					Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 150},
+					FromAccount: account1,
+					ToAccount:   account2,
+				}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "CurrencyDisabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().EnabledCurrency(gomock.Any(), gomock.Eq("EUR")).Times(1).Return(db.Currency{}, db.ErrCurrencyDisabled)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "EmailNotVerified",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().EnabledCurrency(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{Code: "EUR"}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{Username: user.Username}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "FromAccountOfOtherUser",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().EnabledCurrency(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{Code: "EUR"}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "AccountFrozen",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{}, &db.AccountNotActiveError{AccountID: account2.ID, Status: db.AccountStatusFrozen})
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "LimitExceeded",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{}, &db.LimitExceededError{Scope: db.LimitScopeAccount, Limit: db.LimitDailyAmount})
+			},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "InsufficientFunds",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{}, fmt.Errorf("%w: account %d", db.ErrInsufficientFunds, account1.ID))
+			},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "CurrencyMismatch",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, money.ErrCurrencyMismatch)
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "ToAccountNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, db.ErrRecordNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "ForeignKeyViolation",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTxResult{}, &db.ConstraintError{Kind: db.ErrForeignKeyViolation, Constraint: "transfers_to_account_id_fkey"})
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				buildChecks(store)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, sql.ErrConnDone)
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewBufferString(body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		body gin.H
+	}{
+		{"InvalidUsername", gin.H{"username": "invalid-user#1", "password": "secret", "full_name": "Alice", "email": "alice@email.com"}},
+		{"InvalidEmail", gin.H{"username": "alice", "password": "secret", "full_name": "Alice", "email": "invalid-email"}},
+		{"TooShortPassword", gin.H{"username": "alice", "password": "123", "full_name": "Alice", "email": "alice@email.com"}},
+		{"MissingFullName", gin.H{"username": "alice", "password": "secret", "email": "alice@email.com"}},
+	}
+
+	server := newTestServer(t, nil)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestSessionRoutesRequireAuthentication(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, "/users/sessions", nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code, method)
+	}
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** validCurrency accepts ISO 4217 currency codes
+var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if currency, ok := fieldLevel.Field().Interface().(string); ok {
+		return money.IsSupportedCurrency(currency)
+	}
+	return false
+}
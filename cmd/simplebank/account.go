@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+func (app *app) createAccountCommand() *cobra.Command {
+	var owner, currency string
+	cmd := &cobra.Command{
+		Use:   "create-account --owner USERNAME --currency CODE",
+		Short: "Open an empty account for an existing user",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if _, err = store.GetUser(cmd.Context(), owner); err != nil {
+				return fmt.Errorf("owner %q: %w", owner, err)
+			}
+			if _, err = store.EnabledCurrency(cmd.Context(), currency); err != nil {
+				return err
+			}
+
+			account, err := store.CreateAccount(cmd.Context(), db.CreateAccountParams{
+				Owner:    owner,
+				Balance:  0,
+				Currency: currency,
+			})
+			if err != nil {
+				return err
+			}
+			return app.printer().print(account, accountTable(account))
+		},
+	}
+	cmd.Flags().StringVar(&owner, "owner", "", "username of the account owner")
+	cmd.Flags().StringVar(&currency, "currency", "", "ISO 4217 currency code of the account")
+	cmd.MarkFlagRequired("owner")
+	cmd.MarkFlagRequired("currency")
+	return cmd
+}
+
+func (app *app) getAccountCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-account ID",
+		Short: "Show an account",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID("account id", args[0])
+			if err != nil {
+				return err
+			}
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			account, err := store.GetAccount(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+			return app.printer().print(account, accountTable(account))
+		},
+	}
+}
+
+func (app *app) listAccountsCommand() *cobra.Command {
+	var owner string
+	var limit, offset int32
+	cmd := &cobra.Command{
+		Use:   "list-accounts --owner USERNAME",
+		Short: "List the accounts of a user",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 1 || offset < 0 {
+				return usageErrorf("--limit must be positive and --offset not negative")
+			}
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			accounts, err := store.ListAccountsByOwner(cmd.Context(), db.ListAccountsByOwnerParams{
+				Owner:  owner,
+				Limit:  limit,
+				Offset: offset,
+			})
+			if err != nil {
+				return err
+			}
+			if accounts == nil {
+				accounts = []db.Account{}
+			}
+			return app.printer().print(accounts, accountTable(accounts...))
+		},
+	}
+	cmd.Flags().StringVar(&owner, "owner", "", "username of the account owner")
+	cmd.Flags().Int32Var(&limit, "limit", 50, "maximum number of accounts")
+	cmd.Flags().Int32Var(&offset, "offset", 0, "number of accounts to skip")
+	cmd.MarkFlagRequired("owner")
+	return cmd
+}
+
+func (app *app) freezeCommand() *cobra.Command {
+	var unfreeze bool
+	cmd := &cobra.Command{
+		Use:   "freeze ID",
+		Short: "Freeze an account so no money moves in or out, or unfreeze it with --undo",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID("account id", args[0])
+			if err != nil {
+				return err
+			}
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			var account db.Account
+			if unfreeze {
+				account, err = store.UnfreezeAccount(cmd.Context(), id)
+			} else {
+				account, err = store.FreezeAccount(cmd.Context(), id)
+			}
+			if err != nil {
+				return err
+			}
+			return app.printer().print(account, accountTable(account))
+		},
+	}
+	cmd.Flags().BoolVar(&unfreeze, "undo", false, "unfreeze the account instead")
+	return cmd
+}
+
+func (app *app) setOverdraftCommand() *cobra.Command {
+	var limit string
+	cmd := &cobra.Command{
+		Use:   "set-overdraft ID --limit \"500.00 EUR\"",
+		Short: "Grant or change the overdraft facility of an account, a zero limit withdraws it",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID("account id", args[0])
+			if err != nil {
+				return err
+			}
+			value, err := money.Parse(limit)
+			if err != nil {
+				return err
+			}
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			account, err := store.SetOverdraftLimit(cmd.Context(), id, value)
+			if err != nil {
+				return err
+			}
+			return app.printer().print(account, accountTable(account))
+		},
+	}
+	cmd.Flags().StringVar(&limit, "limit", "", "overdraft limit in the currency of the account, e.g. \"500.00 EUR\"")
+	cmd.MarkFlagRequired("limit")
+	return cmd
+}
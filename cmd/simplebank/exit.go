@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** Exit codes, so scripts can tell failures apart without parsing messages
+const (
+	exitOK       = 0
+	exitFailure  = 1 // ** anything not listed below, e.g. the database is unreachable
+	exitUsage    = 2 // ** unknown command, bad flag or argument
+	exitNotFound = 3 // ** the account or transfer does not exist
+	exitInvalid  = 4 // ** the amount or currency is not acceptable
+	exitConflict = 5 // ** the accounts are not in a state that allows the operation
+	exitMismatch = 6 // ** reconcile found accounts whose balance does not match their entries
+)
+
+// ** errMismatch is returned by reconcile when it found accounts to look at
+var errMismatch = errors.New("balances do not match their entries")
+
+// ** usageError marks invalid command lines
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...)}
+}
+
+// ** exitCode maps an error to the exit code of the process
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, db.ErrRecordNotFound), errors.Is(err, db.ErrForeignKeyViolation):
+		return exitNotFound
+	case errors.Is(err, money.ErrInvalidAmount),
+		errors.Is(err, money.ErrOverflow),
+		errors.Is(err, money.ErrCurrencyMismatch),
+		errors.Is(err, money.ErrUnknownCurrency),
+		errors.Is(err, db.ErrUnsupportedCurrency),
+		errors.Is(err, db.ErrCurrencyDisabled),
+		errors.Is(err, db.ErrCheckViolation),
+		errors.Is(err, db.ErrNotNullViolation):
+		return exitInvalid
+	case errors.Is(err, db.ErrAccountFrozen),
+		errors.Is(err, db.ErrAccountClosed),
+		errors.Is(err, db.ErrInvalidStatusTransition),
+		errors.Is(err, db.ErrNonZeroBalance),
+		errors.Is(err, db.ErrConcurrentModification),
+		errors.Is(err, db.ErrLimitExceeded),
+		errors.Is(err, db.ErrInsufficientFunds),
+		errors.Is(err, db.ErrAlreadyReversed),
+		errors.Is(err, db.ErrTransferIsReversal),
+		errors.Is(err, db.ErrUniqueViolation):
+		return exitConflict
+	case errors.Is(err, errMismatch):
+		return exitMismatch
+	}
+	return exitFailure
+}
+
+// ** usageArgs turns the errors of a cobra argument validator into usage errors
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return usageError{err}
+		}
+		return nil
+	}
+}
+
+// ** parseID reads a positive account or transfer id from the command line
+func parseID(name, value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 1 {
+		return 0, usageErrorf("invalid %s %q", name, value)
+	}
+	return id, nil
+}
+
+// ** checkRequiredFlags reports flags marked required but not given as a usage error,
+// **  before cobra reports them as a plain one
+func checkRequiredFlags(cmd *cobra.Command) error {
+	var missing []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if required := flag.Annotations[cobra.BashCompOneRequiredFlag]; len(required) > 0 && required[0] == "true" && !flag.Changed {
+			missing = append(missing, "--"+flag.Name)
+		}
+	})
+	if len(missing) > 0 {
+		return usageErrorf("required flag(s) %s not set", strings.Join(missing, ", "))
+	}
+	return nil
+}
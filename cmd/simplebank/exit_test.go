@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"ok", nil, exitOK},
+		{"usage", usageErrorf("missing command"), exitUsage},
+		{"not found", fmt.Errorf("account: %w", db.ErrRecordNotFound), exitNotFound},
+		{"invalid amount", fmt.Errorf("%w: 1.234", money.ErrInvalidAmount), exitInvalid},
+		{"currency mismatch", money.ErrCurrencyMismatch, exitInvalid},
+		{"unsupported currency", db.ErrUnsupportedCurrency, exitInvalid},
+		{"frozen", &db.AccountNotActiveError{AccountID: 1, Status: db.AccountStatusFrozen}, exitConflict},
+		{"limit", &db.LimitExceededError{Scope: db.LimitScopeAccount, Limit: db.LimitDailyAmount}, exitConflict},
+		{"insufficient funds", fmt.Errorf("%w: account 1", db.ErrInsufficientFunds), exitConflict},
+		{"already reversed", fmt.Errorf("%w: transfer 1", db.ErrAlreadyReversed), exitConflict},
+		{"unique violation", &db.ConstraintError{Kind: db.ErrUniqueViolation, Constraint: "fee_schedules_name_key"}, exitConflict},
+		{"foreign key violation", &db.ConstraintError{Kind: db.ErrForeignKeyViolation, Constraint: "entries_account_id_fkey"}, exitNotFound},
+		{"check violation", &db.ConstraintError{Kind: db.ErrCheckViolation, Constraint: "transfer_limits_check"}, exitInvalid},
+		{"mismatch", errMismatch, exitMismatch},
+		{"other", errors.New("connection refused"), exitFailure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.code, exitCode(tc.err))
+		})
+	}
+}
+
+// ** every case fails before the store is opened, so no database is needed
+func TestRunUsage(t *testing.T) {
+	t.Setenv("USER", "operator")
+
+	testCases := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"withdraw"}, exitUsage},
+		{"unknown flag", []string{"reconcile", "--all"}, exitUsage},
+		{"unknown output", []string{"reconcile", "-o", "yaml"}, exitUsage},
+		{"missing argument", []string{"get-account"}, exitUsage},
+		{"invalid id", []string{"get-account", "abc"}, exitUsage},
+		{"missing flag", []string{"transfer", "--from", "1", "--to", "2"}, exitUsage},
+		{"invalid migration", []string{"migrate", "sideways"}, exitUsage},
+		{"missing limit", []string{"set-overdraft", "1"}, exitUsage},
+		{"invalid date", []string{"export-statement", "1", "--from", "2023-03-01", "--to", "March"}, exitUsage},
+		{"invalid amount", []string{"transfer", "--from", "1", "--to", "2", "--amount", "1.234 EUR"}, exitInvalid},
+		{"negative amount", []string{"transfer", "--from", "1", "--to", "2", "--amount", "-1 EUR"}, exitInvalid},
+		{"unknown currency", []string{"transfer", "--from", "1", "--to", "2", "--amount", "1 XXY"}, exitInvalid},
+		{"empty actor", []string{"--actor", "", "reconcile"}, exitUsage},
+		{"help", []string{"--help"}, exitOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tc.args, &stdout, &stderr)
+			require.Equal(t, tc.code, code, stderr.String())
+			if tc.code != exitOK {
+				require.Contains(t, stderr.String(), "error:")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// ** simplebank is the operator command line tool. It works on the database directly through db.Store,
+// **  configured from app.env like the server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+	"github.com/techschool/simplebank/db/pool"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// ** run executes the command line and returns the process exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	app := &app{stdout: stdout}
+	defer app.close()
+
+	root := app.rootCommand()
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+
+	err := root.ExecuteContext(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+	}
+	return exitCode(err)
+}
+
+// ** app holds the global flags and the lazily opened store shared by all subcommands
+type app struct {
+	configPath string
+	format     string
+	actor      string
+	stdout     io.Writer
+
+	config  *util.Config
+	conn    *pgxpool.Pool
+	replica *pgxpool.Pool
+	store   db.Store
+}
+
+func (app *app) rootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "simplebank",
+		Short:         "Administer the simple bank",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Help()
+			return usageErrorf("missing command")
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if app.format != formatJSON && app.format != formatTable {
+				return usageErrorf("unknown output format %q, use %s or %s", app.format, formatJSON, formatTable)
+			}
+			if app.actor == "" {
+				return usageErrorf("--actor is empty and $USER is not set")
+			}
+			// ** changes made by the tool are audited as made by the operator
+			cmd.SetContext(db.WithAuditActor(cmd.Context(), app.actor))
+			return checkRequiredFlags(cmd)
+		},
+	}
+	root.PersistentFlags().StringVar(&app.configPath, "config", ".", "directory of app.env")
+	root.PersistentFlags().StringVarP(&app.format, "output", "o", formatTable, "output format: table or json")
+	root.PersistentFlags().StringVar(&app.actor, "actor", os.Getenv("USER"), "operator the audit log records changes under")
+
+	// ** flag and argument errors are usage errors, so they get their own exit code
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+
+	root.AddCommand(
+		app.migrateCommand(),
+		app.createAccountCommand(),
+		app.getAccountCommand(),
+		app.listAccountsCommand(),
+		app.transferCommand(),
+		app.reverseCommand(),
+		app.freezeCommand(),
+		app.setOverdraftCommand(),
+		app.reconcileCommand(),
+		app.exportStatementCommand(),
+	)
+	return root
+}
+
+// ** loadConfig reads app.env once
+func (app *app) loadConfig() (util.Config, error) {
+	if app.config == nil {
+		config, err := util.LoadConfig(app.configPath)
+		if err != nil {
+			return util.Config{}, fmt.Errorf("cannot load config: %w", err)
+		}
+		app.config = &config
+	}
+	return *app.config, nil
+}
+
+// ** openStore connects to the database on first use
+func (app *app) openStore(ctx context.Context) (db.Store, error) {
+	if app.store != nil {
+		return app.store, nil
+	}
+	config, err := app.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := pool.Open(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to db: %w", err)
+	}
+	app.conn = conn
+
+	replica, err := pool.OpenReplica(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to db: %w", err)
+	}
+	app.replica = replica
+	app.store = db.NewStore(conn, db.ReadReplica(replica))
+	return app.store, nil
+}
+
+func (app *app) close() {
+	if app.conn != nil {
+		app.conn.Close()
+	}
+	if app.replica != nil {
+		app.replica.Close()
+	}
+}
+
+// ** printer renders results in the format chosen with --output
+func (app *app) printer() printer {
+	return printer{w: app.stdout, format: app.format}
+}
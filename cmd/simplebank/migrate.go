@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/spf13/cobra"
+)
+
+// ** migrateResult is what the schema looks like after migrate ran
+type migrateResult struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+}
+
+func (app *app) migrateCommand() *cobra.Command {
+	var path string
+	var steps int
+	cmd := &cobra.Command{
+		Use:       "migrate up|down",
+		Short:     "Apply or roll back schema migrations",
+		Long:      "Apply all pending migrations (or --steps of them) with up, roll back --steps migrations (one by default) with down.",
+		ValidArgs: []string{"up", "down"},
+		Args:      usageArgs(cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps < 0 {
+				return usageErrorf("--steps must not be negative")
+			}
+			config, err := app.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			m, err := migrate.New("file://"+path, migrateSource(config.DBSource))
+			if err != nil {
+				return fmt.Errorf("cannot create migration: %w", err)
+			}
+			defer m.Close()
+
+			switch {
+			case args[0] == "up" && steps == 0:
+				err = m.Up()
+			case args[0] == "up":
+				err = m.Steps(steps)
+			case steps == 0:
+				err = m.Steps(-1)
+			default:
+				err = m.Steps(-steps)
+			}
+			if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+				return err
+			}
+
+			var result migrateResult
+			result.Version, result.Dirty, err = m.Version()
+			if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+				return err
+			}
+			return app.printer().print(result, table{
+				header: []string{"VERSION", "DIRTY"},
+				rows:   [][]string{{fmt.Sprint(result.Version), fmt.Sprint(result.Dirty)}},
+			})
+		},
+	}
+	cmd.Flags().StringVar(&path, "path", "db/migration", "directory of the migration files")
+	cmd.Flags().IntVar(&steps, "steps", 0, "number of migrations to apply or roll back")
+	return cmd
+}
+
+// ** migrateSource points DB_SOURCE at the pgx/v5 driver of migrate, which is registered as pgx5
+func migrateSource(dbSource string) string {
+	for _, scheme := range []string{"postgresql://", "postgres://"} {
+		if strings.HasPrefix(dbSource, scheme) {
+			return "pgx5://" + strings.TrimPrefix(dbSource, scheme)
+		}
+	}
+	return dbSource
+}
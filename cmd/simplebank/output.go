@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// ** table is the tabular rendering of a result
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// ** printer writes a result as indented JSON or as an aligned table
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// ** print writes value as JSON, or its table as text
+func (p printer) print(value any, t table) error {
+	if p.format == formatJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func accountTable(accounts ...db.Account) table {
+	t := table{header: []string{"ID", "OWNER", "BALANCE", "STATUS", "CREATED AT"}}
+	for _, account := range accounts {
+		t.rows = append(t.rows, []string{
+			strconv.FormatInt(account.ID, 10),
+			account.Owner,
+			account.BalanceMoney().String(),
+			string(account.Status),
+			account.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return t
+}
+
+// ** transferTable shows transfers in the currency of the account the money left
+func transferTable(currency string, transfers ...db.Transfer) table {
+	t := table{header: []string{"ID", "FROM", "TO", "AMOUNT", "CREATED AT"}}
+	for _, transfer := range transfers {
+		t.rows = append(t.rows, []string{
+			strconv.FormatInt(transfer.ID, 10),
+			strconv.FormatInt(transfer.FromAccountID, 10),
+			strconv.FormatInt(transfer.ToAccountID, 10),
+			money.Money{Amount: transfer.Amount, Currency: currency}.String(),
+			transfer.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return t
+}
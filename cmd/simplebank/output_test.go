@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+func TestPrinter(t *testing.T) {
+	account := db.Account{
+		ID:        7,
+		Owner:     "alice",
+		Balance:   12345,
+		Currency:  "EUR",
+		Status:    db.AccountStatusFrozen,
+		CreatedAt: time.Date(2023, time.March, 1, 8, 5, 0, 0, time.UTC),
+		Version:   3,
+	}
+
+	var buf bytes.Buffer
+	err := printer{w: &buf, format: formatTable}.print(account, accountTable(account))
+	require.NoError(t, err)
+	require.Equal(t, "ID  OWNER  BALANCE     STATUS  CREATED AT\n"+
+		"7   alice  123.45 EUR  frozen  2023-03-01T08:05:00Z\n", buf.String())
+
+	buf.Reset()
+	err = printer{w: &buf, format: formatJSON}.print(account, accountTable(account))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"ID": 7,
+		"Owner": "alice",
+		"Balance": 12345,
+		"OverdraftLimit": 0,
+		"Currency": "EUR",
+		"CreatedAt": "2023-03-01T08:05:00Z",
+		"Status": "frozen",
+		"Version": 3
+	}`, buf.String())
+}
+
+func TestReconcileTable(t *testing.T) {
+	var buf bytes.Buffer
+	rows := []db.ListUnreconciledAccountsRow{{ID: 1, Owner: "bob", Currency: "JPY", Balance: 500, EntriesTotal: 450}}
+	err := printer{w: &buf, format: formatTable}.print(rows, reconcileTable(rows))
+	require.NoError(t, err)
+	require.Equal(t, "ID  OWNER  BALANCE  ENTRIES  DIFFERENCE\n"+
+		"1   bob    500 JPY  450 JPY  50 JPY\n", buf.String())
+}
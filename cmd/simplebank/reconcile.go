@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+func (app *app) reconcileCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reconcile",
+		Short: "List accounts whose balance differs from the sum of their entries",
+		Long:  "List accounts whose balance differs from the sum of their entries. Exits with status 6 when there is any.",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			rows, err := store.ListUnreconciledAccounts(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if rows == nil {
+				rows = []db.ListUnreconciledAccountsRow{}
+			}
+			if err = app.printer().print(rows, reconcileTable(rows)); err != nil {
+				return err
+			}
+			if len(rows) > 0 {
+				return errMismatch
+			}
+			return nil
+		},
+	}
+}
+
+func reconcileTable(rows []db.ListUnreconciledAccountsRow) table {
+	t := table{header: []string{"ID", "OWNER", "BALANCE", "ENTRIES", "DIFFERENCE"}}
+	for _, row := range rows {
+		t.rows = append(t.rows, []string{
+			strconv.FormatInt(row.ID, 10),
+			row.Owner,
+			money.Money{Amount: row.Balance, Currency: row.Currency}.String(),
+			money.Money{Amount: row.EntriesTotal, Currency: row.Currency}.String(),
+			money.Money{Amount: row.Balance - row.EntriesTotal, Currency: row.Currency}.String(),
+		})
+	}
+	return t
+}
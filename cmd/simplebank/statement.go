@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/techschool/simplebank/statement"
+)
+
+// ** Statement file formats
+const (
+	statementCamt053 = "camt053"
+	statementMT940   = "mt940"
+)
+
+func (app *app) exportStatementCommand() *cobra.Command {
+	var from, to, format, out string
+	cmd := &cobra.Command{
+		Use:   "export-statement ID --from YYYY-MM-DD --to YYYY-MM-DD",
+		Short: "Write the statement of an account as camt.053 or MT940",
+		Long:  "Write the statement of an account over [--from, --to) in UTC as camt.053 or MT940, to stdout or --out.",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accountID, err := parseID("account id", args[0])
+			if err != nil {
+				return err
+			}
+			fromDate, err := time.Parse(time.DateOnly, from)
+			if err != nil {
+				return usageErrorf("invalid --from date %q", from)
+			}
+			toDate, err := time.Parse(time.DateOnly, to)
+			if err != nil {
+				return usageErrorf("invalid --to date %q", to)
+			}
+			if !fromDate.Before(toDate) {
+				return usageErrorf("--from must be before --to")
+			}
+
+			var write func(io.Writer, statement.Statement) error
+			switch format {
+			case statementCamt053:
+				write = statement.WriteCamt053
+			case statementMT940:
+				write = statement.WriteMT940
+			default:
+				return usageErrorf("unknown statement format %q, use %s or %s", format, statementCamt053, statementMT940)
+			}
+
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+			s, err := statement.Load(cmd.Context(), store, accountID, fromDate, toDate)
+			if err != nil {
+				return err
+			}
+
+			if out == "" {
+				return write(app.stdout, s)
+			}
+			file, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			if err = write(file, s); err != nil {
+				file.Close()
+				return err
+			}
+			return file.Close()
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "first day of the statement")
+	cmd.Flags().StringVar(&to, "to", "", "day after the last day of the statement")
+	cmd.Flags().StringVar(&format, "format", statementCamt053, "file format: camt053 or mt940")
+	cmd.Flags().StringVarP(&out, "out", "f", "", "file to write instead of stdout")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	return cmd
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+func (app *app) transferCommand() *cobra.Command {
+	var fromAccountID, toAccountID, feeScheduleID int64
+	var amount string
+	cmd := &cobra.Command{
+		Use:   "transfer --from ID --to ID --amount \"12.34 EUR\"",
+		Short: "Move money between two accounts of the same currency",
+		Args:  usageArgs(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := money.Parse(amount)
+			if err != nil {
+				return err
+			}
+			arg, err := db.NewTransferTxParams(fromAccountID, toAccountID, value)
+			if err != nil {
+				return err
+			}
+			arg.FeeScheduleID = feeScheduleID
+
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+			result, err := store.TransferTx(cmd.Context(), arg)
+			if err != nil {
+				return err
+			}
+			return app.printer().print(result, transferTable(value.Currency, result.Transfer))
+		},
+	}
+	cmd.Flags().Int64Var(&fromAccountID, "from", 0, "id of the account the money leaves")
+	cmd.Flags().Int64Var(&toAccountID, "to", 0, "id of the account the money goes to")
+	cmd.Flags().StringVar(&amount, "amount", "", "amount and currency, e.g. \"12.34 EUR\"")
+	cmd.Flags().Int64Var(&feeScheduleID, "fee-schedule", 0, "id of the fee schedule to charge the sender by")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	cmd.MarkFlagRequired("amount")
+	return cmd
+}
+
+func (app *app) reverseCommand() *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "reverse TRANSFER_ID --reason TEXT",
+		Short: "Move the money of a transfer back with a transfer in the opposite direction",
+		Args:  usageArgs(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			transferID, err := parseID("transfer id", args[0])
+			if err != nil {
+				return err
+			}
+			store, err := app.openStore(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			result, err := store.ReverseTransferTx(cmd.Context(), db.ReverseTransferTxParams{
+				TransferID: transferID,
+				Reason:     reason,
+			})
+			if err != nil {
+				return err
+			}
+			return app.printer().print(result, transferTable(result.FromAccount.Currency, result.Transfer))
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the transfer is reversed, kept with the reversal")
+	cmd.MarkFlagRequired("reason")
+	return cmd
+}
@@ -0,0 +1,45 @@
+// ** verify-audit walks the audit log and checks its hash chains against their heads.
+// ** It exits with status 1 when a row was changed, removed or inserted after the fact.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/techschool/simplebank/db/pool"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+)
+
+func main() {
+	configPath := flag.String("config", ".", "directory of app.env")
+	flag.Parse()
+
+	config, err := util.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := pool.Open(context.Background(), config)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+	defer conn.Close()
+
+	store := db.NewStore(conn)
+	verified, err := store.VerifyAuditLog(context.Background())
+
+	var chainErr *db.AuditChainError
+	switch {
+	case errors.As(err, &chainErr):
+		fmt.Fprintf(os.Stderr, "audit log is NOT intact after %d rows: %v\n", verified, chainErr)
+		os.Exit(1)
+	case err != nil:
+		log.Fatal("cannot verify audit log:", err)
+	}
+	fmt.Printf("audit log is intact: %d rows verified\n", verified)
+}
@@ -0,0 +1,19 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint UNIQUE,
+  "owner" varchar UNIQUE,
+  "max_amount" bigint,
+  "daily_amount" bigint,
+  "monthly_amount" bigint,
+  "daily_count" bigint,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  CHECK (("account_id" IS NULL) <> ("owner" IS NULL))
+);
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+COMMENT ON TABLE "transfer_limits" IS 'limits either one account or every account of an owner';
+COMMENT ON COLUMN "transfer_limits"."max_amount" IS 'largest single transfer, NULL for no limit';
+COMMENT ON COLUMN "transfer_limits"."daily_count" IS 'number of outgoing transfers per day, NULL for no limit';
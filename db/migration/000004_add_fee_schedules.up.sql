@@ -0,0 +1,21 @@
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "currency" varchar NOT NULL,
+  "fee_account_id" bigint NOT NULL,
+  "fixed_fee" bigint NOT NULL DEFAULT 0,
+  "percentage_bps" bigint NOT NULL DEFAULT 0,
+  "cross_currency_bps" bigint NOT NULL DEFAULT 0,
+  "instant_fee" bigint NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "free_transfers_per_month" bigint NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("fee_account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "fee_schedules"."fee_account_id" IS 'house account credited with the fees';
+COMMENT ON COLUMN "fee_schedules"."percentage_bps" IS 'basis points of the amount, charged over the free tier';
+COMMENT ON COLUMN "fee_schedules"."cross_currency_bps" IS 'basis points of the amount when the accounts have different currencies';
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'NULL for no cap';
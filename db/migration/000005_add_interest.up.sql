@@ -0,0 +1,50 @@
+CREATE TABLE "interest_plans" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "annual_rate_bps" bigint NOT NULL,
+  "day_count" varchar NOT NULL DEFAULT 'ACT/365',
+  "interest_account_id" bigint NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "account_interest_plans" (
+  "account_id" bigint PRIMARY KEY,
+  "interest_plan_id" bigint NOT NULL,
+  "attached_at" TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "interest_plan_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  UNIQUE ("account_id", "accrual_date")
+);
+
+CREATE TABLE "interest_postings" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period_start" date NOT NULL,
+  "period_end" date NOT NULL,
+  "amount" bigint NOT NULL,
+  "entry_id" bigint,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  UNIQUE ("account_id", "period_start")
+);
+
+ALTER TABLE "interest_plans" ADD FOREIGN KEY ("interest_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "account_interest_plans" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "account_interest_plans" ADD FOREIGN KEY ("interest_plan_id") REFERENCES "interest_plans" ("id");
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("interest_plan_id") REFERENCES "interest_plans" ("id");
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+COMMENT ON COLUMN "interest_plans"."day_count" IS 'ACT/365, ACT/360, ACT/ACT or 30E/360';
+COMMENT ON COLUMN "interest_plans"."interest_account_id" IS 'house account paying the interest';
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end of day balance';
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'interest in millionths of a minor unit';
+COMMENT ON COLUMN "interest_postings"."period_end" IS 'exclusive';
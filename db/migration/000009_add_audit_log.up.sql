@@ -0,0 +1,17 @@
+CREATE TABLE "audit_log" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "request_id" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "entity" varchar NOT NULL,
+  "entity_id" bigint NOT NULL,
+  "before" json NOT NULL,
+  "after" json NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "prev_hash" varchar NOT NULL,
+  "hash" varchar UNIQUE NOT NULL
+);
+
+COMMENT ON COLUMN "audit_log"."before" IS 'json keeps the exact text that was hashed, unlike jsonb';
+COMMENT ON COLUMN "audit_log"."prev_hash" IS 'hash of the previous row, empty for the first row';
+COMMENT ON COLUMN "audit_log"."hash" IS 'hex SHA-256 over the content of the row and prev_hash';
@@ -0,0 +1,7 @@
+CREATE TYPE "user_role" AS ENUM (
+  'customer',
+  'banker',
+  'admin'
+);
+
+ALTER TABLE "users" ADD COLUMN "role" user_role NOT NULL DEFAULT 'customer';
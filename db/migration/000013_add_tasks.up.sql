@@ -0,0 +1,25 @@
+CREATE TYPE "task_status" AS ENUM (
+  'pending',
+  'running',
+  'done',
+  'dead'
+);
+
+CREATE TABLE "tasks" (
+  "id" bigserial PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" task_status NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "max_attempts" integer NOT NULL,
+  "run_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  "locked_until" TIMESTAMPTZ,
+  "last_error" varchar,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  "completed_at" TIMESTAMPTZ
+);
+
+COMMENT ON COLUMN "tasks"."locked_until" IS 'a running task whose lease expired is claimed again, e.g. after a worker crashed';
+
+-- only pending and running tasks are ever polled
+CREATE INDEX ON "tasks" ("run_at") WHERE "status" IN ('pending', 'running');
@@ -0,0 +1,8 @@
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_balance_check";
+
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "transfers_accounts_check";
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "transfers_amount_check";
+
+ALTER TABLE "entries" DROP CONSTRAINT IF EXISTS "entries_account_id_check";
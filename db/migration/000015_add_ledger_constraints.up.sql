@@ -0,0 +1,23 @@
+-- the constraints hold for every row written from now on. Rows written before them are not
+--  checked here, so a ledger holding some cannot fail this migration: 000016 covers negative
+--  balances, 000021 validates the rest once they are fixed.
+ALTER TABLE "entries" ADD CONSTRAINT "entries_account_id_check" CHECK ("account_id" IS NOT NULL) NOT VALID;
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_amount_check" CHECK ("amount" > 0) NOT VALID;
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_accounts_check" CHECK ("from_account_id" <> "to_account_id") NOT VALID;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_balance_check" CHECK ("balance" >= 0) NOT VALID;
+
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+CREATE INDEX ON "entries" ("transfer_id");
+
+-- a transfer and its entries are created in one transaction, so they share created_at
+UPDATE "entries" e
+SET "transfer_id" = t."id"
+FROM "transfers" t
+WHERE e."created_at" = t."created_at"
+  AND ((e."account_id" = t."from_account_id" AND e."amount" = -t."amount")
+    OR (e."account_id" = t."to_account_id" AND e."amount" = t."amount"));
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that booked the entry, NULL for adjustments and interest';
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "overdraft_usages";
+
+ALTER TABLE "interest_plans" DROP COLUMN IF EXISTS "overdraft_rate_bps";
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_balance_check";
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_balance_check" CHECK ("balance" >= 0) NOT VALID;
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "overdraft_limit";
@@ -0,0 +1,31 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_overdraft_limit_check" CHECK ("overdraft_limit" >= 0);
+
+-- accounts that went below zero before 000015, e.g. house accounts that paid interest without
+--  the funds, get an overdraft of what they owe: they can be credited, not debited further
+UPDATE "accounts" SET "overdraft_limit" = -"balance" WHERE "balance" < 0;
+
+-- accounts without an overdraft keep a balance of at least zero
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_balance_check";
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_balance_check" CHECK ("balance" >= -"overdraft_limit");
+
+ALTER TABLE "interest_plans" ADD COLUMN "overdraft_rate_bps" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE "overdraft_usages" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "overdrawn" bigint NOT NULL,
+  "overdraft_limit" bigint NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "overdraft_usages" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "overdraft_usages" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "overdraft_usages" ("account_id", "created_at");
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far the balance may go below zero';
+COMMENT ON COLUMN "interest_plans"."overdraft_rate_bps" IS 'annual rate charged on negative balances';
+COMMENT ON TABLE "overdraft_usages" IS 'debits that left an account overdrawn';
+COMMENT ON COLUMN "overdraft_usages"."overdrawn" IS 'how far the balance was below zero after the debit';
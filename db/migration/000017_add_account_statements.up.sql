@@ -0,0 +1,16 @@
+CREATE TABLE "account_statements" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "number" bigint NOT NULL,
+  "period_start" TIMESTAMPTZ NOT NULL,
+  "period_end" TIMESTAMPTZ NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_statements" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE UNIQUE INDEX ON "account_statements" ("account_id", "number");
+CREATE UNIQUE INDEX ON "account_statements" ("account_id", "period_start", "period_end");
+
+COMMENT ON TABLE "account_statements" IS 'statements issued per account, numbered in the order they were first issued';
+COMMENT ON COLUMN "account_statements"."number" IS 'sequence number of the statement within its account, starting at 1';
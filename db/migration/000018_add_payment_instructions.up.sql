@@ -0,0 +1,28 @@
+CREATE TABLE "account_identifiers" (
+  "scheme" varchar NOT NULL,
+  "identifier" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("scheme", "identifier"),
+  CONSTRAINT "account_identifiers_scheme_check" CHECK ("scheme" IN ('IBAN', 'OTHR'))
+);
+
+ALTER TABLE "account_identifiers" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "account_identifiers" ("account_id");
+
+CREATE TABLE "payment_instructions" (
+  "account_id" bigint NOT NULL,
+  "end_to_end_id" varchar NOT NULL,
+  "transfer_id" bigint NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "end_to_end_id")
+);
+
+ALTER TABLE "payment_instructions" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "payment_instructions" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON TABLE "account_identifiers" IS 'identifiers payment files use for our accounts';
+COMMENT ON COLUMN "account_identifiers"."scheme" IS 'IBAN, or OTHR for a proprietary identifier';
+COMMENT ON TABLE "payment_instructions" IS 'end-to-end ids of executed payment instructions, unique per debtor account';
+COMMENT ON COLUMN "payment_instructions"."account_id" IS 'debtor account';
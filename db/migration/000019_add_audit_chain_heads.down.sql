@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "audit_chain_heads";
+ALTER TABLE "audit_log" DROP COLUMN IF EXISTS "chain";
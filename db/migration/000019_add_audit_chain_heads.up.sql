@@ -0,0 +1,21 @@
+ALTER TABLE "audit_log" ADD COLUMN "chain" integer NOT NULL DEFAULT 0;
+ALTER TABLE "audit_log" ALTER COLUMN "chain" DROP DEFAULT;
+
+CREATE TABLE "audit_chain_heads" (
+  "chain" integer PRIMARY KEY,
+  "hash" varchar NOT NULL,
+  "row_count" bigint NOT NULL
+);
+
+-- the log written so far is chain 0, the others start empty
+INSERT INTO "audit_chain_heads" ("chain", "hash", "row_count")
+SELECT c, '', 0 FROM generate_series(0, 15) AS c;
+
+UPDATE "audit_chain_heads" SET
+  "hash" = COALESCE((SELECT "hash" FROM "audit_log" ORDER BY "id" DESC LIMIT 1), ''),
+  "row_count" = (SELECT COUNT(*) FROM "audit_log")
+WHERE "chain" = 0;
+
+COMMENT ON COLUMN "audit_log"."chain" IS 'the hash chain the row belongs to, rows link to the previous row of their chain';
+COMMENT ON TABLE "audit_chain_heads" IS 'last hash and row count of every audit log chain, so rows removed from the end are noticed';
+COMMENT ON COLUMN "audit_chain_heads"."hash" IS 'hash of the last row of the chain, empty while it has none';
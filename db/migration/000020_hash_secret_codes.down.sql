@@ -0,0 +1,6 @@
+-- hashes cannot be turned back into codes: the codes issued so far no longer work
+UPDATE "password_resets" SET "is_used" = true;
+ALTER TABLE "password_resets" RENAME COLUMN "secret_code_hash" TO "secret_code";
+
+UPDATE "verify_emails" SET "is_used" = true;
+ALTER TABLE "verify_emails" RENAME COLUMN "secret_code_hash" TO "secret_code";
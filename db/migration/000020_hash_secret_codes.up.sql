@@ -0,0 +1,9 @@
+-- codes already issued keep working: they are hashed the way the Store hashes them
+ALTER TABLE "verify_emails" RENAME COLUMN "secret_code" TO "secret_code_hash";
+UPDATE "verify_emails" SET "secret_code_hash" = encode(sha256(convert_to("secret_code_hash", 'UTF8')), 'hex');
+
+ALTER TABLE "password_resets" RENAME COLUMN "secret_code" TO "secret_code_hash";
+UPDATE "password_resets" SET "secret_code_hash" = encode(sha256(convert_to("secret_code_hash", 'UTF8')), 'hex');
+
+COMMENT ON COLUMN "verify_emails"."secret_code_hash" IS 'hex SHA-256 of the code, which only the email carries';
+COMMENT ON COLUMN "password_resets"."secret_code_hash" IS 'hex SHA-256 of the code, which only the email carries';
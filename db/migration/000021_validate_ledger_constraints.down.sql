@@ -0,0 +1,2 @@
+ALTER TABLE "entries" ALTER COLUMN "account_id" DROP NOT NULL;
+ALTER TABLE "entries" ADD CONSTRAINT "entries_account_id_check" CHECK ("account_id" IS NOT NULL) NOT VALID;
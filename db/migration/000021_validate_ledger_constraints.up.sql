@@ -0,0 +1,8 @@
+-- checks the rows written before 000015. If it fails, correct the rows it reports and run it again.
+ALTER TABLE "transfers" VALIDATE CONSTRAINT "transfers_amount_check";
+ALTER TABLE "transfers" VALIDATE CONSTRAINT "transfers_accounts_check";
+
+-- the validated check lets SET NOT NULL skip scanning the table
+ALTER TABLE "entries" VALIDATE CONSTRAINT "entries_account_id_check";
+ALTER TABLE "entries" ALTER COLUMN "account_id" SET NOT NULL;
+ALTER TABLE "entries" DROP CONSTRAINT "entries_account_id_check";
@@ -0,0 +1,120 @@
+// ** Package pool opens the pgxpool connection pool configured in app.env,
+// **  with its own sizing, health checks and statement cache.
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/techschool/simplebank/util"
+)
+
+// ** ErrInvalidConfig is returned when the pool settings cannot be applied
+var ErrInvalidConfig = errors.New("invalid database config")
+
+// ** queryExecModes maps DB_QUERY_EXEC_MODE to how pgx sends queries.
+// ** Only cache_statement prepares statements; behind PgBouncer use exec or simple_protocol.
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// ** Open connects to the database and checks it answers
+func Open(ctx context.Context, config util.Config) (*pgxpool.Pool, error) {
+	return open(ctx, config, false)
+}
+
+// ** OpenReplica connects to the read replica in DB_REPLICA_SOURCE with the same pool settings.
+// ** It returns a nil pool when no replica is configured. Every transaction on it is read only,
+// **  so a write routed there by mistake fails instead of diverging from the primary.
+func OpenReplica(ctx context.Context, config util.Config) (*pgxpool.Pool, error) {
+	if config.DBReplicaSource == "" {
+		return nil, nil
+	}
+	config.DBSource = config.DBReplicaSource
+
+	connPool, err := open(ctx, config, true)
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+	return connPool, nil
+}
+
+// ** ParseConfig builds the pgxpool config from DB_SOURCE and the DB_* pool settings.
+// ** Zero settings keep the pgx defaults.
+func ParseConfig(config util.Config) (*pgxpool.Config, error) {
+	if err := checkSizes(config); err != nil {
+		return nil, err
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(config.DBSource)
+	if err != nil {
+		return nil, fmt.Errorf("%w: DB_SOURCE: %v", ErrInvalidConfig, err)
+	}
+
+	if config.DBMaxConns > 0 {
+		poolConfig.MaxConns = config.DBMaxConns
+	}
+	if config.DBMinConns > 0 {
+		poolConfig.MinConns = config.DBMinConns
+	}
+	if config.DBMaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.DBMaxConnLifetime
+	}
+	if config.DBMaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.DBMaxConnIdleTime
+	}
+	if config.DBHealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = config.DBHealthCheckPeriod
+	}
+
+	if config.DBQueryExecMode != "" {
+		mode, ok := queryExecModes[config.DBQueryExecMode]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown DB_QUERY_EXEC_MODE %q", ErrInvalidConfig, config.DBQueryExecMode)
+		}
+		poolConfig.ConnConfig.DefaultQueryExecMode = mode
+	}
+	if config.DBStatementCacheSize > 0 {
+		poolConfig.ConnConfig.StatementCacheCapacity = config.DBStatementCacheSize
+	}
+	return poolConfig, nil
+}
+
+func checkSizes(config util.Config) error {
+	switch {
+	case config.DBMaxConns < 0 || config.DBMinConns < 0 || config.DBStatementCacheSize < 0:
+		return fmt.Errorf("%w: pool and cache sizes cannot be negative", ErrInvalidConfig)
+	case config.DBMaxConns > 0 && config.DBMinConns > config.DBMaxConns:
+		return fmt.Errorf("%w: DB_MIN_CONNS %d is above DB_MAX_CONNS %d", ErrInvalidConfig, config.DBMinConns, config.DBMaxConns)
+	case config.DBMaxConnLifetime < 0 || config.DBMaxConnIdleTime < 0 || config.DBHealthCheckPeriod < 0:
+		return fmt.Errorf("%w: pool durations cannot be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+func open(ctx context.Context, config util.Config, readOnly bool) (*pgxpool.Pool, error) {
+	poolConfig, err := ParseConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		poolConfig.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+	}
+
+	connPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create pool: %w", err)
+	}
+	if err := connPool.Ping(ctx); err != nil {
+		connPool.Close()
+		return nil, fmt.Errorf("cannot reach database: %w", err)
+	}
+	return connPool, nil
+}
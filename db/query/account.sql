@@ -12,6 +12,13 @@ SELECT * FROM accounts
 WHERE id = $1 
 LIMIT 1;
 
+-- name: GetAccountBalanceBefore :one
+SELECT (a.balance - COALESCE(SUM(e.amount), 0))::bigint AS balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= sqlc.arg(before)
+WHERE a.id = sqlc.arg(account_id)
+GROUP BY a.id;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1
@@ -22,17 +29,26 @@ FOR NO KEY UPDATE;
 SELECT * FROM accounts
 ORDER BY id
 LIMIT $1
-OFFSET $2; 
+OFFSET $2;
+
+-- name: ListAccountsByOwner :many
+SELECT * FROM accounts
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
 
 -- name: UpdateAccount :one
 UPDATE accounts
-SET balance = $2
+SET balance = $2,
+    version = version + 1
 WHERE id = $1
 RETURNING *;
 
 -- name: AddAccountBalance :one
 UPDATE accounts
-SET balance = balance + sqlc.arg(amount)
+SET balance = balance + sqlc.arg(amount),
+    version = version + 1
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
@@ -41,3 +57,33 @@ RETURNING *;
  WHERE id = $1;
 
 
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2,
+    version = version + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountIfVersion :one
+UPDATE accounts
+SET balance = sqlc.arg(balance),
+    version = version + 1
+WHERE id = sqlc.arg(id)
+  AND version = sqlc.arg(expected_version)
+RETURNING *;
+
+-- name: UpdateAccountStatusIfVersion :one
+UPDATE accounts
+SET status = sqlc.arg(status),
+    version = version + 1
+WHERE id = sqlc.arg(id)
+  AND version = sqlc.arg(expected_version)
+RETURNING *;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2,
+    version = version + 1
+WHERE id = $1
+RETURNING *;
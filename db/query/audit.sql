@@ -0,0 +1,41 @@
+-- name: GetAuditChainHeadForUpdate :one
+SELECT * FROM audit_chain_heads
+WHERE chain = $1
+FOR UPDATE;
+
+-- name: AdvanceAuditChainHead :exec
+UPDATE audit_chain_heads
+SET hash = $2, row_count = row_count + 1
+WHERE chain = $1;
+
+-- name: ListAuditChainHeads :many
+SELECT * FROM audit_chain_heads
+ORDER BY chain;
+
+-- name: GetLastAuditLog :one
+SELECT * FROM audit_log
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateAuditLog :one
+INSERT INTO audit_log (
+    actor,
+    request_id,
+    action,
+    entity,
+    entity_id,
+    before,
+    after,
+    created_at,
+    prev_hash,
+    hash,
+    chain
+) VALUES (
+    $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11
+) RETURNING *;
+
+-- name: ListAuditLog :many
+SELECT * FROM audit_log
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(limit_rows);
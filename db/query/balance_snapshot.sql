@@ -0,0 +1,23 @@
+-- name: CreateBalanceSnapshots :execrows
+INSERT INTO account_balance_snapshots (
+    account_id,
+    snapshot_at,
+    balance
+)
+SELECT a.id,
+       sqlc.arg(snapshot_at)::timestamptz,
+       a.balance - COALESCE((
+           SELECT SUM(e.amount) FROM entries e
+           WHERE e.account_id = a.id
+             AND e.created_at >= sqlc.arg(snapshot_at)::timestamptz
+       ), 0)::bigint
+FROM accounts a
+WHERE a.created_at < sqlc.arg(snapshot_at)::timestamptz
+ON CONFLICT (account_id, snapshot_at) DO NOTHING;
+
+-- name: GetLatestBalanceSnapshot :one
+SELECT * FROM account_balance_snapshots
+WHERE account_id = sqlc.arg(account_id)
+  AND snapshot_at <= sqlc.arg(at)
+ORDER BY snapshot_at DESC
+LIMIT 1;
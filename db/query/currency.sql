@@ -0,0 +1,19 @@
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1
+LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: ListEnabledCurrencies :many
+SELECT * FROM currencies
+WHERE enabled
+ORDER BY code;
+
+-- name: SetCurrencyEnabled :one
+UPDATE currencies
+SET enabled = $2
+WHERE code = $1
+RETURNING *;
@@ -1,9 +1,10 @@
 -- name: CreateEntry :one
 INSERT INTO entries(
     account_id,
-    amount
+    amount,
+    transfer_id
 ) VALUES (
-    $1,$2
+    $1,$2,$3
 )  RETURNING *;
 
 -- name: GetEntry :one
@@ -26,3 +27,22 @@ RETURNING *;
 -- name: DeleteEntry :exec
 DELETE FROM entries
 WHERE id = $1;
+
+
+-- name: ListAccountEntriesBetween :many
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time)
+ORDER BY created_at, id;
+
+-- name: SumAccountEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since);
+
+-- name: SumAccountEntriesBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(from_time)
+  AND created_at < sqlc.arg(to_time);
@@ -0,0 +1,30 @@
+-- name: CreateFeeSchedule :one
+INSERT INTO fee_schedules (
+    name,
+    currency,
+    fee_account_id,
+    fixed_fee,
+    percentage_bps,
+    cross_currency_bps,
+    instant_fee,
+    min_fee,
+    max_fee,
+    free_transfers_per_month
+) VALUES (
+    $1,$2,$3,$4,$5,$6,$7,$8,$9,$10
+) RETURNING *;
+
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: DeleteFeeSchedule :exec
+DELETE FROM fee_schedules
+WHERE id = $1;
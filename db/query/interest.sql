@@ -0,0 +1,90 @@
+-- name: CreateInterestPlan :one
+INSERT INTO interest_plans (
+    name,
+    annual_rate_bps,
+    day_count,
+    interest_account_id,
+    overdraft_rate_bps
+) VALUES (
+    $1,$2,$3,$4,$5
+) RETURNING *;
+
+-- name: GetInterestPlan :one
+SELECT * FROM interest_plans
+WHERE id = $1
+LIMIT 1;
+
+-- name: AttachInterestPlan :one
+INSERT INTO account_interest_plans (
+    account_id,
+    interest_plan_id
+) VALUES (
+    $1,$2
+) ON CONFLICT (account_id) DO UPDATE SET
+    interest_plan_id = EXCLUDED.interest_plan_id,
+    attached_at = now()
+RETURNING *;
+
+-- name: DetachInterestPlan :exec
+DELETE FROM account_interest_plans
+WHERE account_id = $1;
+
+-- name: ListInterestBearingAccounts :many
+SELECT ap.account_id, ap.interest_plan_id, p.annual_rate_bps, p.day_count, p.interest_account_id, p.overdraft_rate_bps, a.status
+FROM account_interest_plans ap
+JOIN interest_plans p ON p.id = ap.interest_plan_id
+JOIN accounts a ON a.id = ap.account_id
+WHERE a.status <> 'closed'
+ORDER BY ap.account_id;
+
+-- name: CreateInterestAccrual :execrows
+INSERT INTO interest_accruals (
+    account_id,
+    interest_plan_id,
+    accrual_date,
+    balance,
+    amount_micros
+) VALUES (
+    $1,$2,$3,$4,$5
+) ON CONFLICT (account_id, accrual_date) DO NOTHING;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND accrual_date >= sqlc.arg(from_date)
+  AND accrual_date < sqlc.arg(to_date)
+ORDER BY accrual_date;
+
+-- name: SumInterestAccruals :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint AS total FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND accrual_date >= sqlc.arg(from_date)
+  AND accrual_date < sqlc.arg(to_date);
+
+-- name: SumInterestAccrualsBefore :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint AS total FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+  AND accrual_date < sqlc.arg(before);
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+    account_id,
+    period_start,
+    period_end,
+    amount,
+    entry_id
+) VALUES (
+    $1,$2,$3,$4,$5
+) ON CONFLICT (account_id, period_start) DO NOTHING
+RETURNING *;
+
+-- name: GetInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1 AND period_start = $2
+LIMIT 1;
+
+-- name: SumInterestPostings :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total,
+       COUNT(*) FILTER (WHERE period_start > sqlc.arg(after)) AS later_periods
+FROM interest_postings
+WHERE account_id = sqlc.arg(account_id);
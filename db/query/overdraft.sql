@@ -0,0 +1,16 @@
+-- name: CreateOverdraftUsage :one
+INSERT INTO overdraft_usages (
+    account_id,
+    transfer_id,
+    overdrawn,
+    overdraft_limit
+) VALUES (
+    $1,$2,$3,$4
+) RETURNING *;
+
+-- name: ListOverdraftUsages :many
+SELECT * FROM overdraft_usages
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
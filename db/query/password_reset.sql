@@ -0,0 +1,22 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+    username,
+    secret_code_hash,
+    expired_at
+) VALUES (
+    $1,$2,$3
+) RETURNING *;
+
+-- name: UsePasswordReset :one
+UPDATE password_resets
+SET is_used = true
+WHERE id = $1
+  AND secret_code_hash = $2
+  AND is_used = false
+  AND expired_at > now()
+RETURNING *;
+
+-- name: GetPasswordReset :one
+SELECT * FROM password_resets
+WHERE id = $1
+LIMIT 1;
@@ -0,0 +1,26 @@
+-- name: CreateAccountIdentifier :one
+INSERT INTO account_identifiers (
+    scheme,
+    identifier,
+    account_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetAccountByIdentifier :one
+SELECT * FROM accounts
+WHERE id = (
+    SELECT account_id FROM account_identifiers
+    WHERE scheme = $1 AND identifier = $2
+) LIMIT 1;
+
+-- name: CreatePaymentInstruction :one
+INSERT INTO payment_instructions (
+    account_id,
+    end_to_end_id,
+    transfer_id
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (account_id, end_to_end_id) DO NOTHING
+RETURNING *;
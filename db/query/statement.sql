@@ -0,0 +1,13 @@
+-- name: CreateAccountStatement :one
+INSERT INTO account_statements (
+    account_id,
+    number,
+    period_start,
+    period_end
+)
+SELECT sqlc.arg(account_id), COALESCE(MAX(number), 0) + 1, sqlc.arg(period_start), sqlc.arg(period_end)
+FROM account_statements
+WHERE account_id = sqlc.arg(account_id)
+ON CONFLICT (account_id, period_start, period_end) DO UPDATE
+SET period_end = EXCLUDED.period_end
+RETURNING *;
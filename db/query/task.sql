@@ -0,0 +1,59 @@
+-- name: CreateTask :one
+INSERT INTO tasks (
+    type,
+    payload,
+    max_attempts,
+    run_at
+) VALUES (
+    $1,$2,$3,$4
+) RETURNING *;
+
+-- name: GetTask :one
+SELECT * FROM tasks
+WHERE id = $1
+LIMIT 1;
+
+-- name: ClaimTask :one
+UPDATE tasks
+SET status = 'running',
+    attempts = attempts + 1,
+    locked_until = now() + sqlc.arg(lease_seconds)::float8 * interval '1 second'
+WHERE id = (
+    SELECT id FROM tasks
+    WHERE (status = 'pending' AND run_at <= now())
+       OR (status = 'running' AND locked_until < now())
+    ORDER BY run_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteTask :execrows
+UPDATE tasks
+SET status = 'done',
+    payload = '{}',
+    locked_until = NULL,
+    completed_at = now()
+WHERE id = $1
+  AND status = 'running'
+  AND locked_until = $2;
+
+-- name: RetryTask :execrows
+UPDATE tasks
+SET status = 'pending',
+    locked_until = NULL,
+    run_at = $2,
+    last_error = $3
+WHERE id = $1
+  AND status = 'running'
+  AND locked_until = $4;
+
+-- name: KillTask :execrows
+UPDATE tasks
+SET status = 'dead',
+    payload = '{}',
+    locked_until = NULL,
+    last_error = $2
+WHERE id = $1
+  AND status = 'running'
+  AND locked_until = $3;
@@ -0,0 +1,61 @@
+-- name: SetAccountTransferLimit :one
+INSERT INTO transfer_limits (
+    account_id,
+    max_amount,
+    daily_amount,
+    monthly_amount,
+    daily_count
+) VALUES (
+    $1,$2,$3,$4,$5
+) ON CONFLICT (account_id) DO UPDATE SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    daily_count = EXCLUDED.daily_count
+RETURNING *;
+
+-- name: SetOwnerTransferLimit :one
+INSERT INTO transfer_limits (
+    owner,
+    max_amount,
+    daily_amount,
+    monthly_amount,
+    daily_count
+) VALUES (
+    $1,$2,$3,$4,$5
+) ON CONFLICT (owner) DO UPDATE SET
+    max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    daily_count = EXCLUDED.daily_count
+RETURNING *;
+
+-- name: GetAccountTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE account_id = sqlc.arg(account_id)::bigint
+LIMIT 1;
+
+-- name: GetOwnerTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE owner = sqlc.arg(owner)::varchar
+LIMIT 1;
+
+-- name: DeleteTransferLimit :exec
+DELETE FROM transfer_limits
+WHERE id = $1;
+
+-- name: GetAccountTransferTotals :one
+SELECT COUNT(*) AS count, COALESCE(SUM(amount), 0)::bigint AS total
+FROM transfers
+WHERE from_account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since);
+
+-- name: GetOwnerTransferTotals :one
+SELECT COUNT(*) AS count, COALESCE(SUM(t.amount) FILTER (WHERE a.currency = sqlc.arg(currency)), 0)::bigint AS total
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = sqlc.arg(owner)
+  AND t.created_at >= sqlc.arg(since);
+
+-- name: LockOwnerTransfers :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(owner)::text));
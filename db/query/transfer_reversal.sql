@@ -0,0 +1,13 @@
+-- name: CreateTransferReversal :one
+INSERT INTO transfer_reversals (
+    transfer_id,
+    reversal_id,
+    reason
+) VALUES (
+    $1,$2,$3
+) RETURNING *;
+
+-- name: GetTransferReversal :one
+SELECT * FROM transfer_reversals
+WHERE transfer_id = $1 OR reversal_id = $1
+LIMIT 1;
@@ -0,0 +1,23 @@
+-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails (
+    username,
+    email,
+    secret_code_hash,
+    expired_at
+) VALUES (
+    $1,$2,$3,$4
+) RETURNING *;
+
+-- name: UseVerifyEmail :one
+UPDATE verify_emails
+SET is_used = true
+WHERE id = $1
+  AND secret_code_hash = $2
+  AND is_used = false
+  AND expired_at > now()
+RETURNING *;
+
+-- name: GetVerifyEmail :one
+SELECT * FROM verify_emails
+WHERE id = $1
+LIMIT 1;
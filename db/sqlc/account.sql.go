@@ -7,13 +7,15 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
-SET balance = balance + $1
+SET balance = balance + $1,
+    version = version + 1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
 `
 
 type AddAccountBalanceParams struct {
@@ -22,7 +24,7 @@ type AddAccountBalanceParams struct {
 }
 
 func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
-	row := q.queryRow(ctx, q.addAccountBalanceStmt, addAccountBalance, arg.Amount, arg.ID)
+	row := q.db.QueryRow(ctx, addAccountBalance, arg.Amount, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -30,6 +32,9 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
@@ -41,7 +46,7 @@ INSERT INTO accounts (
     currency
 ) VALUES (
     $1,$2,$3
-) RETURNING id, owner, balance, currency, created_at
+) RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
 `
 
 type CreateAccountParams struct {
@@ -51,7 +56,7 @@ type CreateAccountParams struct {
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.queryRow(ctx, q.createAccountStmt, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +64,9 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
@@ -69,18 +77,18 @@ const deleteAccount = `-- name: DeleteAccount :exec
 `
 
 func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.exec(ctx, q.deleteAccountStmt, deleteAccount, id)
+	_, err := q.db.Exec(ctx, deleteAccount, id)
 	return err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, status, version, overdraft_limit FROM accounts
 WHERE id = $1 
 LIMIT 1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
-	row := q.queryRow(ctx, q.getAccountStmt, getAccount, id)
+	row := q.db.QueryRow(ctx, getAccount, id)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -88,19 +96,42 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
 
+const getAccountBalanceBefore = `-- name: GetAccountBalanceBefore :one
+SELECT (a.balance - COALESCE(SUM(e.amount), 0))::bigint AS balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= $1
+WHERE a.id = $2
+GROUP BY a.id
+`
+
+type GetAccountBalanceBeforeParams struct {
+	Before    time.Time
+	AccountID int64
+}
+
+func (q *Queries) GetAccountBalanceBefore(ctx context.Context, arg GetAccountBalanceBeforeParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getAccountBalanceBefore, arg.Before, arg.AccountID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, status, version, overdraft_limit FROM accounts
 WHERE id = $1
 LIMIT 1
 FOR NO KEY UPDATE
 `
 
 func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
-	row := q.queryRow(ctx, q.getAccountForUpdateStmt, getAccountForUpdate, id)
+	row := q.db.QueryRow(ctx, getAccountForUpdate, id)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -108,12 +139,15 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, status, version, overdraft_limit FROM accounts
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -125,7 +159,7 @@ type ListAccountsParams struct {
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.query(ctx, q.listAccountsStmt, listAccounts, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -139,14 +173,57 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Status,
+			&i.Version,
+			&i.OverdraftLimit,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByOwner = `-- name: ListAccountsByOwner :many
+SELECT id, owner, balance, currency, created_at, status, version, overdraft_limit FROM accounts
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
+`
+
+type ListAccountsByOwnerParams struct {
+	Owner  string
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListAccountsByOwner(ctx context.Context, arg ListAccountsByOwnerParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsByOwner, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.Status,
+			&i.Version,
+			&i.OverdraftLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -155,9 +232,10 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts
-SET balance = $2
+SET balance = $2,
+    version = version + 1
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
 `
 
 type UpdateAccountParams struct {
@@ -166,7 +244,127 @@ type UpdateAccountParams struct {
 }
 
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.queryRow(ctx, q.updateAccountStmt, updateAccount, arg.ID, arg.Balance)
+	row := q.db.QueryRow(ctx, updateAccount, arg.ID, arg.Balance)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
+
+const updateAccountIfVersion = `-- name: UpdateAccountIfVersion :one
+UPDATE accounts
+SET balance = $1,
+    version = version + 1
+WHERE id = $2
+  AND version = $3
+RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
+`
+
+type UpdateAccountIfVersionParams struct {
+	Balance         int64
+	ID              int64
+	ExpectedVersion int64
+}
+
+func (q *Queries) UpdateAccountIfVersion(ctx context.Context, arg UpdateAccountIfVersionParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountIfVersion, arg.Balance, arg.ID, arg.ExpectedVersion)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2,
+    version = version + 1
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	ID             int64
+	OverdraftLimit int64
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountOverdraftLimit, arg.ID, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $2,
+    version = version + 1
+WHERE id = $1
+RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
+`
+
+type UpdateAccountStatusParams struct {
+	ID     int64
+	Status AccountStatus
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.ID, arg.Status)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
+	)
+	return i, err
+}
+
+const updateAccountStatusIfVersion = `-- name: UpdateAccountStatusIfVersion :one
+UPDATE accounts
+SET status = $1,
+    version = version + 1
+WHERE id = $2
+  AND version = $3
+RETURNING id, owner, balance, currency, created_at, status, version, overdraft_limit
+`
+
+type UpdateAccountStatusIfVersionParams struct {
+	Status          AccountStatus
+	ID              int64
+	ExpectedVersion int64
+}
+
+func (q *Queries) UpdateAccountStatusIfVersion(ctx context.Context, arg UpdateAccountStatusIfVersionParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatusIfVersion, arg.Status, arg.ID, arg.ExpectedVersion)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -174,6 +372,9 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Status,
+		&i.Version,
+		&i.OverdraftLimit,
 	)
 	return i, err
 }
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ** Lifecycle errors returned by the Store
+var (
+	ErrAccountFrozen           = errors.New("account is frozen")
+	ErrAccountClosed           = errors.New("account is closed")
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	ErrNonZeroBalance          = errors.New("account balance is not zero")
+)
+
+// ** AccountNotActiveError is returned when money is moved from or to an account that is not active.
+// ** It unwraps to ErrAccountFrozen or ErrAccountClosed.
+type AccountNotActiveError struct {
+	AccountID int64
+	Status    AccountStatus
+}
+
+func (e *AccountNotActiveError) Error() string {
+	return fmt.Sprintf("account %d is %s", e.AccountID, e.Status)
+}
+
+func (e *AccountNotActiveError) Unwrap() error {
+	switch e.Status {
+	case AccountStatusFrozen:
+		return ErrAccountFrozen
+	case AccountStatusClosed:
+		return ErrAccountClosed
+	}
+	return nil
+}
+
+// ** allowed account status transitions, closed is final
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive},
+}
+
+// ** CanTransitionTo reports whether an account in this status may move to the next one
+func (s AccountStatus) CanTransitionTo(next AccountStatus) bool {
+	for _, allowed := range accountStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ** checkAccountActive returns an *AccountNotActiveError unless the account is active
+func checkAccountActive(account Account) error {
+	if account.Status != AccountStatusActive {
+		return &AccountNotActiveError{AccountID: account.ID, Status: account.Status}
+	}
+	return nil
+}
+
+// ** FreezeAccount blocks all debits and credits on an active account
+func (store *SQLStore) FreezeAccount(ctx context.Context, accountID int64) (Account, error) {
+	return store.ChangeAccountStatus(ctx, accountID, AccountStatusFrozen, 0)
+}
+
+// ** UnfreezeAccount makes a frozen account active again
+func (store *SQLStore) UnfreezeAccount(ctx context.Context, accountID int64) (Account, error) {
+	return store.ChangeAccountStatus(ctx, accountID, AccountStatusActive, 0)
+}
+
+// ** CloseAccount closes an active account for good. The balance must be zero.
+// ** There are no holds to check: nothing reserves funds ahead of booking, every debit is booked
+// **  by the transaction that moves the money, so a zero balance means nothing is owed or pending.
+// ** Unlike DeleteAccount it keeps the account row, so entries and transfers stay referenced.
+func (store *SQLStore) CloseAccount(ctx context.Context, accountID int64) (Account, error) {
+	return store.ChangeAccountStatus(ctx, accountID, AccountStatusClosed, 0)
+}
+
+// ** ChangeAccountStatus moves an account to a new status following the lifecycle rules.
+// ** With a non-zero expectedVersion it fails with ErrConcurrentModification if the account changed meanwhile.
+func (store *SQLStore) ChangeAccountStatus(ctx context.Context, accountID int64, status AccountStatus, expectedVersion int64) (Account, error) {
+	var result Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		if expectedVersion != 0 {
+			if err = checkVersion(account, expectedVersion); err != nil {
+				return err
+			}
+		}
+		if !account.Status.CanTransitionTo(status) {
+			return fmt.Errorf("%w: account %d from %s to %s", ErrInvalidStatusTransition, accountID, account.Status, status)
+		}
+		if status == AccountStatusClosed && account.Balance != 0 {
+			return fmt.Errorf("%w: account %d has balance %d", ErrNonZeroBalance, accountID, account.Balance)
+		}
+
+		result, err = q.UpdateAccountStatusIfVersion(ctx, UpdateAccountStatusIfVersionParams{
+			Status:          status,
+			ID:              accountID,
+			ExpectedVersion: account.Version,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "account.status", auditChange{
+			Entity:   "account",
+			EntityID: accountID,
+			Before:   account,
+			After:    result,
+		})
+	})
+	return result, err
+}
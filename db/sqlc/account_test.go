@@ -2,23 +2,27 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
-	"github.com/techschool/simplebank/util"
 )
 
 // ** for testing we should create an account first
-func createRandomAccount(t *testing.T) Account {
+func createRandomAccount(t *testing.T, q *Queries) Account {
+	return createRandomAccountIn(t, q, testRand.Currency())
+}
+
+// ** accounts that exchange money in a test must hold the same currency.
+// ** Balances cannot go negative, so every account starts with enough for the transfers of a test.
+func createRandomAccountIn(t *testing.T, q *Queries, currency string) Account {
 	arg := CreateAccountParams{
-		Owner:    util.RandomOwner(),
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Owner:    testRand.Owner(),
+		Balance:  1000 + testRand.Money(),
+		Currency: currency,
 	}
 
-	account, error := testQueries.CreateAccount(context.Background(), arg)
+	account, error := q.CreateAccount(context.Background(), arg)
 	require.NoError(t, error)
 	require.NotEmpty(t, account)
 
@@ -28,19 +32,27 @@ func createRandomAccount(t *testing.T) Account {
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
+	require.Equal(t, AccountStatusActive, account.Status)
+	require.Equal(t, int64(1), account.Version)
 
 	return account
 }
 
 // ** Test Create Account
 func TestCreateAccount(t *testing.T) {
-	createRandomAccount(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	createRandomAccount(t, q)
 }
 
 // ** Test Get Account
 func TestGetAccount(t *testing.T) {
-	account1 := createRandomAccount(t)
-	account2, error := testQueries.GetAccount(context.Background(), account1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account1 := createRandomAccount(t, q)
+	account2, error := q.GetAccount(context.Background(), account1.ID)
 	require.NoError(t, error)
 	require.NotEmpty(t, account2)
 
@@ -56,12 +68,15 @@ func TestGetAccount(t *testing.T) {
 
 // ** Test Update Account
 func TestUpdateAccount(t *testing.T) {
-	account1 := createRandomAccount(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account1 := createRandomAccount(t, q)
 	arg := UpdateAccountParams{
 		ID:      account1.ID,
-		Balance: util.RandomMoney(),
+		Balance: testRand.Money(),
 	}
-	account2, error := testQueries.UpdateAccount(context.Background(), arg)
+	account2, error := q.UpdateAccount(context.Background(), arg)
 	require.NoError(t, error)
 	require.NotEmpty(t, account2)
 
@@ -71,27 +86,34 @@ func TestUpdateAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 
 	require.Equal(t, arg.Balance, account2.Balance)
+	require.Equal(t, account1.Version+1, account2.Version)
 }
 
 // ** Test Delete Account
 func TestDeleteAccount(t *testing.T) {
-	account1 := createRandomAccount(t)
-	error := testQueries.DeleteAccount(context.Background(), account1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account1 := createRandomAccount(t, q)
+	error := q.DeleteAccount(context.Background(), account1.ID)
 	require.NoError(t, error)
 
 	// ** is account really deleted?
-	account2, error2 := testQueries.GetAccount(context.Background(), account1.ID)
+	account2, error2 := q.GetAccount(context.Background(), account1.ID)
 	require.Error(t, error2)
-	require.EqualError(t, error2, sql.ErrNoRows.Error())
+	require.EqualError(t, error2, ErrRecordNotFound.Error())
 	require.Empty(t, account2)
 }
 
 
 // ** Test List Accounts
 func TestListAccounts(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
 	// ** create 10 random account
 	for i := 0; i < 10; i++ {
-		createRandomAccount(t)
+		createRandomAccount(t, q)
 	}
 
 	arg := ListAccountsParams{ // ** which means skip the first 5 records and return the next 5.
@@ -100,7 +122,7 @@ func TestListAccounts(t *testing.T) {
 	}
 
 	// ** when we run the test, there will be at least 10 accounts in the database, so with these parameters we expect to get 5 records
-	accounts, error := testQueries.ListAccounts(context.Background(), arg)
+	accounts, error := q.ListAccounts(context.Background(), arg)
 	require.NoError(t, error)
 	require.Len(t, accounts, 5)
 
@@ -109,3 +131,125 @@ func TestListAccounts(t *testing.T) {
 	}
 
 }
+
+// ** Test List Accounts By Owner
+func TestListAccountsByOwner(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	// ** one owner with 6 accounts, created between the accounts of other owners
+	owner := testRand.Owner()
+	for i := 0; i < 6; i++ {
+		_, err := q.CreateAccount(context.Background(), CreateAccountParams{Owner: owner, Currency: testRand.Currency()})
+		require.NoError(t, err)
+		createRandomAccount(t, q)
+	}
+
+	arg := ListAccountsByOwnerParams{
+		Owner:  owner,
+		Limit:  5,
+		Offset: 0,
+	}
+
+	accounts, error := q.ListAccountsByOwner(context.Background(), arg)
+	require.NoError(t, error)
+	require.Len(t, accounts, int(arg.Limit))
+
+	for _, account := range accounts {
+		require.Equal(t, owner, account.Owner)
+	}
+
+	// ** the next page holds the last one
+	arg.Offset = 5
+	accounts, error = q.ListAccountsByOwner(context.Background(), arg)
+	require.NoError(t, error)
+	require.Len(t, accounts, 1)
+	require.Equal(t, owner, accounts[0].Owner)
+}
+
+// ** Test Update Account Status
+func TestUpdateAccountStatus(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account1 := createRandomAccount(t, q)
+	arg := UpdateAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusFrozen,
+	}
+	account2, error := q.UpdateAccountStatus(context.Background(), arg)
+	require.NoError(t, error)
+	require.NotEmpty(t, account2)
+
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, AccountStatusFrozen, account2.Status)
+	require.Equal(t, account1.Version+1, account2.Version)
+}
+
+// ** Test Get Account Balance Before
+func TestGetAccountBalanceBefore(t *testing.T) {
+	account1 := createRandomAccount(t, testQueries)
+	account2 := createRandomAccountIn(t, testQueries, account1.Currency)
+
+	before := time.Now()
+	_, err := NewStore(testDB).TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	balance, err := testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		Before:    before,
+		AccountID: account1.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, balance)
+
+	balance, err = testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		Before:    time.Now(),
+		AccountID: account1.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, balance)
+
+	_, err = testQueries.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		Before:    time.Now(),
+		AccountID: -1,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+// ** Test Create Account Books Opening Balance
+func TestCreateAccountBooksOpeningBalance(t *testing.T) {
+	store := NewStore(testDB)
+	before := time.Now()
+
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    testRand.Owner(),
+		Balance:  100,
+		Currency: testRand.Currency(),
+	})
+	require.NoError(t, err)
+
+	// ** the opening balance is an entry: nothing before the account, everything after
+	balance, err := store.GetAccountBalanceBefore(context.Background(), GetAccountBalanceBeforeParams{
+		Before:    before,
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, balance)
+
+	// ** an account whose balance was written without an entry is reported
+	unbooked := createRandomAccount(t, testQueries)
+
+	rows, err := store.ListUnreconciledAccounts(context.Background())
+	require.NoError(t, err)
+	var ids []int64
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	require.NotContains(t, ids, account.ID)
+	require.Contains(t, ids, unbooked.ID)
+}
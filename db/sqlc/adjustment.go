@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// ** AdjustBalanceTxParams contains the input parameters of a manual balance adjustment
+type AdjustBalanceTxParams struct {
+	AccountID int64       `json:"account_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+// ** AdjustBalanceTxResult is the result of a manual balance adjustment
+type AdjustBalanceTxResult struct {
+	Account Account `json:"account"`
+	Entry   Entry   `json:"entry"`
+}
+
+// ** AdjustBalanceTx corrects the balance of an account by a signed amount in its currency.
+// ** The correction is booked as a single entry without a counterpart, so it is audited
+// **  as "account.adjust" together with the account before and after.
+// ** Like any other booking it is refused on a frozen or closed account,
+// **  and cannot take the balance below the overdraft limit.
+func (store *SQLStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	if arg.Amount.IsZero() {
+		return result, fmt.Errorf("%w: adjustment must not be zero", money.ErrInvalidAmount)
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if err = checkAccountActive(before); err != nil {
+			return err
+		}
+		if before.Currency != arg.Amount.Currency {
+			return fmt.Errorf("%w: account %d holds %s, adjustment is in %s", money.ErrCurrencyMismatch, before.ID, before.Currency, arg.Amount.Currency)
+		}
+		if arg.Amount.IsNegative() {
+			if err = checkFunds(before, -arg.Amount.Amount); err != nil {
+				return err
+			}
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount.Amount,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "account.adjust", auditChange{
+			Entity:   "account",
+			EntityID: arg.AccountID,
+			Before:   before,
+			After:    result.Account,
+		})
+	})
+	return result, err
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** Test Adjust Balance Tx
+func TestAdjustBalanceTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+
+	amount := money.Money{Amount: 10 - account1.Balance, Currency: account1.Currency}
+	result, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account1.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), result.Account.Balance)
+	require.Equal(t, account1.Version+1, result.Account.Version)
+	require.Equal(t, account1.ID, result.Entry.AccountID)
+	require.Equal(t, amount.Amount, result.Entry.Amount)
+	require.False(t, result.Entry.TransferID.Valid)
+
+	// ** the balance cannot be adjusted below zero
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account1.ID,
+		Amount:    money.Money{Amount: -11, Currency: account1.Currency},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account1.ID,
+		Amount:    money.Money{Currency: account1.Currency},
+	})
+	require.ErrorIs(t, err, money.ErrInvalidAmount)
+
+	other := "EUR"
+	if account1.Currency == other {
+		other = "USD"
+	}
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account1.ID,
+		Amount:    money.Money{Amount: 10, Currency: other},
+	})
+	require.ErrorIs(t, err, money.ErrCurrencyMismatch)
+}
+
+// ** Test Adjust Balance Tx on a frozen account
+func TestAdjustBalanceTxFrozen(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account := createRandomAccount(t, q)
+
+	_, err := store.FreezeAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    money.Money{Amount: 10, Currency: account.Currency},
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updated.Balance)
+}
+
+// ** Test Adjust Balance Tx within the overdraft limit
+func TestAdjustBalanceTxOverdraft(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+
+	account := createOverdraftAccount(t, store, "EUR", 50)
+
+	result, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    money.Money{Amount: -account.Balance - 50, Currency: account.Currency},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-50), result.Account.Balance)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID: account.ID,
+		Amount:    money.Money{Amount: -1, Currency: account.Currency},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(-50), updated.Balance)
+}
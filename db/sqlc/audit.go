@@ -0,0 +1,284 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ** ErrAuditChainBroken is returned by the verifier when a row of the audit log
+// **  was changed, removed or inserted after the fact
+var ErrAuditChainBroken = errors.New("audit log hash chain is broken")
+
+// ** AuditChainError tells which audit log row breaks the chain and why
+type AuditChainError struct {
+	ID     int64
+	Reason string
+}
+
+func (e *AuditChainError) Error() string {
+	return fmt.Sprintf("audit log row %d: %s", e.ID, e.Reason)
+}
+
+func (e *AuditChainError) Unwrap() error {
+	return ErrAuditChainBroken
+}
+
+// ** mutations without an actor in the context are recorded as made by the system
+const systemActor = "system"
+
+type auditContextKey int
+
+const (
+	auditActorKey auditContextKey = iota
+	auditRequestIDKey
+)
+
+// ** WithAuditActor returns a context whose Store mutations are recorded as made by actor
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey, actor)
+}
+
+// ** WithRequestID returns a context whose Store mutations are recorded with the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, auditRequestIDKey, requestID)
+}
+
+func auditActor(ctx context.Context) string {
+	if actor, ok := ctx.Value(auditActorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return systemActor
+}
+
+func auditRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(auditRequestIDKey).(string)
+	return requestID
+}
+
+// ** auditChange describes what a mutation did to one entity
+type auditChange struct {
+	Entity   string
+	EntityID int64
+	Before   interface{}
+	After    interface{}
+}
+
+// ** execAuditedTx runs fn in a transaction and records its change in the audit log in the same transaction
+func (store *SQLStore) execAuditedTx(ctx context.Context, action string, fn func(*Queries) (auditChange, error)) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		change, err := fn(q)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, action, change)
+	})
+}
+
+// ** auditChains is the number of hash chains of the audit log, each with a row in audit_chain_heads (see migration 000019)
+const auditChains = 16
+
+// ** auditChainOf picks the chain of an actor's rows. Writers on different chains don't wait for each other,
+// **  and the rows of one actor stay in order on one chain.
+func auditChainOf(actor string) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(actor))
+	return int32(h.Sum32() % auditChains)
+}
+
+// ** recordAudit appends a row to the audit log, chained to the last row of its chain.
+// ** The head row of the chain stays locked until the transaction ends, so the rows of a chain are chained
+// **  in commit order and the head always holds the hash and row count of the chain's last row.
+// ** It is taken last, after any account locks, so it cannot be part of a deadlock.
+func recordAudit(ctx context.Context, q *Queries, action string, change auditChange) error {
+	before, err := json.Marshal(change.Before)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(change.After)
+	if err != nil {
+		return err
+	}
+
+	actor := auditActor(ctx)
+	head, err := q.GetAuditChainHeadForUpdate(ctx, auditChainOf(actor))
+	if err != nil {
+		return err
+	}
+
+	arg := CreateAuditLogParams{
+		Actor:     actor,
+		RequestID: auditRequestID(ctx),
+		Action:    action,
+		Entity:    change.Entity,
+		EntityID:  change.EntityID,
+		Before:    before,
+		After:     after,
+		// ** Postgres keeps microseconds, so hash what will be read back
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		PrevHash:  head.Hash,
+		Chain:     head.Chain,
+	}
+	arg.Hash, err = auditHash(arg)
+	if err != nil {
+		return err
+	}
+	if _, err = q.CreateAuditLog(ctx, arg); err != nil {
+		return err
+	}
+	return q.AdvanceAuditChainHead(ctx, AdvanceAuditChainHeadParams{Chain: head.Chain, Hash: arg.Hash})
+}
+
+// ** auditHash is the hex SHA-256 of the canonical JSON encoding of a row's content and the previous hash
+func auditHash(arg CreateAuditLogParams) (string, error) {
+	content, err := json.Marshal(struct {
+		PrevHash  string          `json:"prev_hash"`
+		Actor     string          `json:"actor"`
+		RequestID string          `json:"request_id"`
+		Action    string          `json:"action"`
+		Entity    string          `json:"entity"`
+		EntityID  int64           `json:"entity_id"`
+		Before    json.RawMessage `json:"before"`
+		After     json.RawMessage `json:"after"`
+		CreatedAt string          `json:"created_at"`
+	}{
+		PrevHash:  arg.PrevHash,
+		Actor:     arg.Actor,
+		RequestID: arg.RequestID,
+		Action:    arg.Action,
+		Entity:    arg.Entity,
+		EntityID:  arg.EntityID,
+		Before:    arg.Before,
+		After:     arg.After,
+		CreatedAt: arg.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ** VerifyAuditChain checks that every row of one chain links to the hash before it and that its hash matches its content.
+// ** prevHash is the hash of the row before the first one ("" at the start of the chain).
+// ** It returns the hash of the last row so long logs can be verified page by page.
+func VerifyAuditChain(prevHash string, logs []AuditLog) (string, error) {
+	for _, log := range logs {
+		if log.PrevHash != prevHash {
+			return "", &AuditChainError{ID: log.ID, Reason: "does not link to the previous row"}
+		}
+
+		hash, err := auditHash(CreateAuditLogParams{
+			Actor:     log.Actor,
+			RequestID: log.RequestID,
+			Action:    log.Action,
+			Entity:    log.Entity,
+			EntityID:  log.EntityID,
+			Before:    log.Before,
+			After:     log.After,
+			CreatedAt: log.CreatedAt,
+			PrevHash:  log.PrevHash,
+		})
+		if err != nil {
+			return "", &AuditChainError{ID: log.ID, Reason: err.Error()}
+		}
+		if hash != log.Hash {
+			return "", &AuditChainError{ID: log.ID, Reason: "content does not match its hash"}
+		}
+		prevHash = log.Hash
+	}
+	return prevHash, nil
+}
+
+// ** auditPageSize is the number of rows the verifier reads at a time
+const auditPageSize = 1000
+
+// ** auditChainState is how far the verifier got along one chain
+type auditChainState struct {
+	hash   string
+	count  int64
+	lastID int64
+}
+
+// ** VerifyAuditLog walks the whole audit log and returns the number of verified rows.
+// ** Every row must link to the previous row of its chain, and every chain must end where its head says,
+// **  so rows removed from the end of a chain are noticed too.
+// ** It reads in one snapshot, so rows committed meanwhile are neither half seen nor missing from a head.
+// ** It fails with an *AuditChainError at the first row or chain that is broken.
+func (store *SQLStore) VerifyAuditLog(ctx context.Context) (int, error) {
+	verified := 0
+	err := store.execSnapshot(ctx, func(q *Queries) error {
+		chains := make(map[int32]*auditChainState)
+		var afterID int64
+		for {
+			logs, err := q.ListAuditLog(ctx, ListAuditLogParams{AfterID: afterID, LimitRows: auditPageSize})
+			if err != nil {
+				return err
+			}
+			if len(logs) == 0 {
+				break
+			}
+
+			for i, log := range logs {
+				chain, ok := chains[log.Chain]
+				if !ok {
+					chain = &auditChainState{}
+					chains[log.Chain] = chain
+				}
+				if chain.hash, err = VerifyAuditChain(chain.hash, logs[i:i+1]); err != nil {
+					return err
+				}
+				chain.count++
+				chain.lastID = log.ID
+				verified++
+			}
+			afterID = logs[len(logs)-1].ID
+		}
+
+		heads, err := q.ListAuditChainHeads(ctx)
+		if err != nil {
+			return err
+		}
+		for _, head := range heads {
+			chain, ok := chains[head.Chain]
+			if !ok {
+				chain = &auditChainState{}
+			}
+			if chain.hash != head.Hash || chain.count != head.RowCount {
+				return &AuditChainError{
+					ID:     chain.lastID,
+					Reason: fmt.Sprintf("chain %d ends after %d rows, its head expects %d ending in %q", head.Chain, chain.count, head.RowCount, head.Hash),
+				}
+			}
+			delete(chains, head.Chain)
+		}
+		for c, chain := range chains {
+			return &AuditChainError{ID: chain.lastID, Reason: fmt.Sprintf("chain %d has no head", c)}
+		}
+		return nil
+	})
+	return verified, err
+}
+
+// ** execSnapshot runs fn in a read-only repeatable read transaction, so all its reads see the same snapshot.
+// ** A Store created by NewTxStore runs it in a savepoint of its transaction instead.
+func (store *SQLStore) execSnapshot(ctx context.Context, fn func(*Queries) error) error {
+	if store.tx != nil {
+		return ClassifyError(store.execSavepoint(ctx, fn))
+	}
+
+	tx, err := store.connPool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	return ClassifyError(fn(New(tx)))
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// ** buildAuditChain chains n rows the same way recordAudit does
+func buildAuditChain(t *testing.T, n int) []AuditLog {
+	var logs []AuditLog
+	prevHash := ""
+	for i := 0; i < n; i++ {
+		arg := CreateAuditLogParams{
+			Actor:     "alice",
+			RequestID: testRand.String(8),
+			Action:    "account.update",
+			Entity:    "account",
+			EntityID:  int64(i + 1),
+			Before:    json.RawMessage(`{"Balance":1}`),
+			After:     json.RawMessage(`{"Balance":2}`),
+			CreatedAt: time.Date(2023, time.March, 1, 8, 0, i, 123456000, time.UTC),
+			PrevHash:  prevHash,
+		}
+		hash, err := auditHash(arg)
+		require.NoError(t, err)
+
+		logs = append(logs, AuditLog{
+			ID:        int64(i + 1),
+			Actor:     arg.Actor,
+			RequestID: arg.RequestID,
+			Action:    arg.Action,
+			Entity:    arg.Entity,
+			EntityID:  arg.EntityID,
+			Before:    arg.Before,
+			After:     arg.After,
+			CreatedAt: arg.CreatedAt,
+			PrevHash:  arg.PrevHash,
+			Hash:      hash,
+		})
+		prevHash = hash
+	}
+	return logs
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	logs := buildAuditChain(t, 3)
+
+	last, err := VerifyAuditChain("", logs)
+	require.NoError(t, err)
+	require.Equal(t, logs[2].Hash, last)
+
+	// ** pages chain through the returned hash
+	prevHash, err := VerifyAuditChain("", logs[:1])
+	require.NoError(t, err)
+	_, err = VerifyAuditChain(prevHash, logs[1:])
+	require.NoError(t, err)
+
+	// ** the same instant in another time zone hashes the same
+	moved := append([]AuditLog(nil), logs...)
+	moved[1].CreatedAt = moved[1].CreatedAt.In(time.FixedZone("CET", 3600))
+	_, err = VerifyAuditChain("", moved)
+	require.NoError(t, err)
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	testCases := []struct {
+		name   string
+		tamper func(logs []AuditLog) []AuditLog
+		id     int64
+	}{
+		{"ChangedContent", func(logs []AuditLog) []AuditLog {
+			logs[1].After = json.RawMessage(`{"Balance":1000}`)
+			return logs
+		}, 2},
+		{"ChangedActor", func(logs []AuditLog) []AuditLog {
+			logs[0].Actor = "mallory"
+			return logs
+		}, 1},
+		{"RemovedRow", func(logs []AuditLog) []AuditLog {
+			return append(logs[:1], logs[2:]...)
+		}, 3},
+		{"RehashedRow", func(logs []AuditLog) []AuditLog {
+			// ** recomputing the hash of a changed row still breaks the link of the next one
+			logs[1].EntityID = 42
+			hash, _ := auditHash(CreateAuditLogParams{
+				Actor: logs[1].Actor, RequestID: logs[1].RequestID, Action: logs[1].Action,
+				Entity: logs[1].Entity, EntityID: logs[1].EntityID, Before: logs[1].Before,
+				After: logs[1].After, CreatedAt: logs[1].CreatedAt, PrevHash: logs[1].PrevHash,
+			})
+			logs[1].Hash = hash
+			return logs
+		}, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := tc.tamper(buildAuditChain(t, 3))
+
+			_, err := VerifyAuditChain("", logs)
+			require.ErrorIs(t, err, ErrAuditChainBroken)
+
+			var chainErr *AuditChainError
+			require.ErrorAs(t, err, &chainErr)
+			require.Equal(t, tc.id, chainErr.ID)
+		})
+	}
+}
+
+// ** Test Store Mutations Are Audited
+func TestStoreMutationsAreAudited(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := WithRequestID(WithAuditActor(context.Background(), "banker-1"), "req-"+testRand.String(8))
+
+	account, err := store.CreateAccount(ctx, CreateAccountParams{
+		Owner:    testRand.Owner(),
+		Balance:  100,
+		Currency: testRand.Currency(),
+	})
+	require.NoError(t, err)
+
+	last, err := store.GetLastAuditLog(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "banker-1", last.Actor)
+	require.Equal(t, auditRequestID(ctx), last.RequestID)
+	require.Equal(t, "account.create", last.Action)
+	require.Equal(t, account.ID, last.EntityID)
+	require.JSONEq(t, "null", string(last.Before))
+
+	_, err = store.FreezeAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+
+	last2, err := store.GetLastAuditLog(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, systemActor, last2.Actor)
+	require.Equal(t, "account.status", last2.Action)
+	require.Greater(t, last2.ID, last.ID)
+
+	verified, err := store.VerifyAuditLog(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, verified, 2)
+}
+
+// ** Test Verify Audit Log Detects Truncation
+func TestVerifyAuditLogDetectsTruncation(t *testing.T) {
+	ctx := WithAuditActor(context.Background(), "banker-"+testRand.String(8))
+	account, err := NewStore(testDB).CreateAccount(ctx, CreateAccountParams{
+		Owner:    testRand.Owner(),
+		Balance:  100,
+		Currency: testRand.Currency(),
+	})
+	require.NoError(t, err)
+
+	// ** remove the row again in a transaction that is rolled back, and verify what it sees
+	tx, err := testDB.BeginTx(context.Background(), pgx.TxOptions{IsoLevel: pgx.RepeatableRead})
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+	store := NewTxStore(tx)
+
+	_, err = store.VerifyAuditLog(context.Background())
+	require.NoError(t, err)
+
+	// ** as the last row of its chain, the rows left still link: only the head tells it is missing
+	_, err = tx.Exec(context.Background(),
+		`DELETE FROM audit_log WHERE action = 'account.create' AND entity_id = $1`, account.ID)
+	require.NoError(t, err)
+
+	_, err = store.VerifyAuditLog(context.Background())
+	require.ErrorIs(t, err, ErrAuditChainBroken)
+}
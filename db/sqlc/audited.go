@@ -0,0 +1,370 @@
+package db
+
+import (
+	"context"
+)
+
+// ** The Store shadows every mutating query of Queries with a version that runs in a
+// **  transaction together with its audit log row. Derived data written by jobs
+// **  (interest accruals, balance snapshots) is not audited; it can be recomputed from the ledger.
+// ** Neither are overdraft usages and payment instructions, which TransferTx records next to
+// **  the audited transfer, nor the numbers of issued statements (see statement.go), which move no money.
+// ** Neither is the background task queue (see task.sql), which carries IDs of audited rows and the codes to email.
+// ** Sessions are audited when they are revoked (see session.go), not when they are created at login.
+// ** Likewise email verifications and password resets are audited when they are used, so their
+// **  secret codes never reach the audit log (see verify_email.go and password_reset.go).
+
+// ** CreateAccount is audited as "account.create".
+// ** An opening balance is booked as an entry, so the balance still matches the sum of the entries.
+func (store *SQLStore) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.create", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return auditChange{}, err
+		}
+		if row.Balance != 0 {
+			_, err = q.CreateEntry(ctx, CreateEntryParams{AccountID: row.ID, Amount: row.Balance})
+		}
+		return auditChange{Entity: "account", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateAccount is audited as "account.update"
+func (store *SQLStore) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.update", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateAccount(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** AddAccountBalance is audited as "account.add_balance"
+func (store *SQLStore) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.add_balance", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.AddAccountBalance(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateAccountIfVersion is audited as "account.update"
+func (store *SQLStore) UpdateAccountIfVersion(ctx context.Context, arg UpdateAccountIfVersionParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.update", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateAccountIfVersion(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateAccountOverdraftLimit is audited as "account.overdraft_limit"
+func (store *SQLStore) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.overdraft_limit", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateAccountOverdraftLimit(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateAccountStatus is audited as "account.status"
+func (store *SQLStore) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.status", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateAccountStatus(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateAccountStatusIfVersion is audited as "account.status"
+func (store *SQLStore) UpdateAccountStatusIfVersion(ctx context.Context, arg UpdateAccountStatusIfVersionParams) (Account, error) {
+	var row Account
+	err := store.execAuditedTx(ctx, "account.status", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateAccountStatusIfVersion(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** DeleteAccount is audited as "account.delete"
+func (store *SQLStore) DeleteAccount(ctx context.Context, id int64) error {
+	err := store.execAuditedTx(ctx, "account.delete", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return auditChange{}, err
+		}
+		err = q.DeleteAccount(ctx, id)
+		return auditChange{Entity: "account", EntityID: id, Before: before}, err
+	})
+	return err
+}
+
+// ** CreateAccountIdentifier is audited as "account.identifier"
+func (store *SQLStore) CreateAccountIdentifier(ctx context.Context, arg CreateAccountIdentifierParams) (AccountIdentifier, error) {
+	var row AccountIdentifier
+	err := store.execAuditedTx(ctx, "account.identifier", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateAccountIdentifier(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.AccountID, After: row}, err
+	})
+	return row, err
+}
+
+// ** CreateEntry is audited as "entry.create"
+func (store *SQLStore) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	var row Entry
+	err := store.execAuditedTx(ctx, "entry.create", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateEntry(ctx, arg)
+		return auditChange{Entity: "entry", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateEntry is audited as "entry.update"
+func (store *SQLStore) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error) {
+	var row Entry
+	err := store.execAuditedTx(ctx, "entry.update", func(q *Queries) (auditChange, error) {
+		before, err := q.GetEntry(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateEntry(ctx, arg)
+		return auditChange{Entity: "entry", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** DeleteEntry is audited as "entry.delete"
+func (store *SQLStore) DeleteEntry(ctx context.Context, id int64) error {
+	err := store.execAuditedTx(ctx, "entry.delete", func(q *Queries) (auditChange, error) {
+		before, err := q.GetEntry(ctx, id)
+		if err != nil {
+			return auditChange{}, err
+		}
+		err = q.DeleteEntry(ctx, id)
+		return auditChange{Entity: "entry", EntityID: id, Before: before}, err
+	})
+	return err
+}
+
+// ** CreateTransfer is audited as "transfer.create"
+func (store *SQLStore) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
+	var row Transfer
+	err := store.execAuditedTx(ctx, "transfer.create", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateTransfer(ctx, arg)
+		return auditChange{Entity: "transfer", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** UpdateTransfer is audited as "transfer.update"
+func (store *SQLStore) UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error) {
+	var row Transfer
+	err := store.execAuditedTx(ctx, "transfer.update", func(q *Queries) (auditChange, error) {
+		before, err := q.GetTransfer(ctx, arg.ID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateTransfer(ctx, arg)
+		return auditChange{Entity: "transfer", EntityID: arg.ID, Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** DeleteTransfer is audited as "transfer.delete"
+func (store *SQLStore) DeleteTransfer(ctx context.Context, id int64) error {
+	err := store.execAuditedTx(ctx, "transfer.delete", func(q *Queries) (auditChange, error) {
+		before, err := q.GetTransfer(ctx, id)
+		if err != nil {
+			return auditChange{}, err
+		}
+		err = q.DeleteTransfer(ctx, id)
+		return auditChange{Entity: "transfer", EntityID: id, Before: before}, err
+	})
+	return err
+}
+
+// ** SetCurrencyEnabled is audited as "currency.enable"
+func (store *SQLStore) SetCurrencyEnabled(ctx context.Context, arg SetCurrencyEnabledParams) (Currency, error) {
+	var row Currency
+	err := store.execAuditedTx(ctx, "currency.enable", func(q *Queries) (auditChange, error) {
+		before, err := q.GetCurrency(ctx, arg.Code)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.SetCurrencyEnabled(ctx, arg)
+		return auditChange{Entity: "currency", Before: before, After: row}, err
+	})
+	return row, err
+}
+
+// ** SetAccountTransferLimit is audited as "transfer_limit.set"
+func (store *SQLStore) SetAccountTransferLimit(ctx context.Context, arg SetAccountTransferLimitParams) (TransferLimit, error) {
+	var row TransferLimit
+	err := store.execAuditedTx(ctx, "transfer_limit.set", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.SetAccountTransferLimit(ctx, arg)
+		return auditChange{Entity: "transfer_limit", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** SetOwnerTransferLimit is audited as "transfer_limit.set"
+func (store *SQLStore) SetOwnerTransferLimit(ctx context.Context, arg SetOwnerTransferLimitParams) (TransferLimit, error) {
+	var row TransferLimit
+	err := store.execAuditedTx(ctx, "transfer_limit.set", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.SetOwnerTransferLimit(ctx, arg)
+		return auditChange{Entity: "transfer_limit", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** DeleteTransferLimit is audited as "transfer_limit.delete"
+func (store *SQLStore) DeleteTransferLimit(ctx context.Context, id int64) error {
+	err := store.execAuditedTx(ctx, "transfer_limit.delete", func(q *Queries) (auditChange, error) {
+		err := q.DeleteTransferLimit(ctx, id)
+		return auditChange{Entity: "transfer_limit", EntityID: id}, err
+	})
+	return err
+}
+
+// ** CreateFeeSchedule is audited as "fee_schedule.create"
+func (store *SQLStore) CreateFeeSchedule(ctx context.Context, arg CreateFeeScheduleParams) (FeeSchedule, error) {
+	var row FeeSchedule
+	err := store.execAuditedTx(ctx, "fee_schedule.create", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateFeeSchedule(ctx, arg)
+		return auditChange{Entity: "fee_schedule", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** DeleteFeeSchedule is audited as "fee_schedule.delete"
+func (store *SQLStore) DeleteFeeSchedule(ctx context.Context, id int64) error {
+	err := store.execAuditedTx(ctx, "fee_schedule.delete", func(q *Queries) (auditChange, error) {
+		before, err := q.GetFeeSchedule(ctx, id)
+		if err != nil {
+			return auditChange{}, err
+		}
+		err = q.DeleteFeeSchedule(ctx, id)
+		return auditChange{Entity: "fee_schedule", EntityID: id, Before: before}, err
+	})
+	return err
+}
+
+// ** CreateInterestPlan is audited as "interest_plan.create"
+func (store *SQLStore) CreateInterestPlan(ctx context.Context, arg CreateInterestPlanParams) (InterestPlan, error) {
+	var row InterestPlan
+	err := store.execAuditedTx(ctx, "interest_plan.create", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateInterestPlan(ctx, arg)
+		return auditChange{Entity: "interest_plan", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** AttachInterestPlan is audited as "interest_plan.attach"
+func (store *SQLStore) AttachInterestPlan(ctx context.Context, arg AttachInterestPlanParams) (AccountInterestPlan, error) {
+	var row AccountInterestPlan
+	err := store.execAuditedTx(ctx, "interest_plan.attach", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.AttachInterestPlan(ctx, arg)
+		return auditChange{Entity: "account", EntityID: arg.AccountID, After: row}, err
+	})
+	return row, err
+}
+
+// ** DetachInterestPlan is audited as "interest_plan.detach"
+func (store *SQLStore) DetachInterestPlan(ctx context.Context, accountID int64) error {
+	err := store.execAuditedTx(ctx, "interest_plan.detach", func(q *Queries) (auditChange, error) {
+		err := q.DetachInterestPlan(ctx, accountID)
+		return auditChange{Entity: "account", EntityID: accountID}, err
+	})
+	return err
+}
+
+// ** CreateInterestPosting is audited as "interest.post"
+func (store *SQLStore) CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error) {
+	var row InterestPosting
+	err := store.execAuditedTx(ctx, "interest.post", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateInterestPosting(ctx, arg)
+		return auditChange{Entity: "interest_posting", EntityID: row.ID, After: row}, err
+	})
+	return row, err
+}
+
+// ** CreateUser is audited as "user.create", without the password hash
+func (store *SQLStore) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	var row User
+	err := store.execAuditedTx(ctx, "user.create", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateUser(ctx, arg)
+		return auditChange{Entity: "user", After: userAudit(row)}, err
+	})
+	return row, err
+}
+
+// ** UpdateUserRole is audited as "user.role", without the password hash; UpdateUserRoleTx also revokes the sessions
+func (store *SQLStore) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	var row User
+	err := store.execAuditedTx(ctx, "user.role", func(q *Queries) (auditChange, error) {
+		before, err := q.GetUser(ctx, arg.Username)
+		if err != nil {
+			return auditChange{}, err
+		}
+		row, err = q.UpdateUserRole(ctx, arg)
+		return auditChange{Entity: "user", Before: userAudit(before), After: userAudit(row)}, err
+	})
+	return row, err
+}
+
+// ** userAudit leaves the password hash out of the audit log
+func userAudit(user User) User {
+	user.HashedPassword = ""
+	return user
+}
+
+// ** CreateTransferReversal is audited as "transfer.reversal"; use ReverseTransferTx to also move the money
+func (store *SQLStore) CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (TransferReversal, error) {
+	var row TransferReversal
+	err := store.execAuditedTx(ctx, "transfer.reversal", func(q *Queries) (auditChange, error) {
+		var err error
+		row, err = q.CreateTransferReversal(ctx, arg)
+		return auditChange{Entity: "transfer", EntityID: row.TransferID, After: row}, err
+	})
+	return row, err
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ** GetBalanceAt returns the balance of an account at a point in time, i.e. including every
+// **  entry created before it. It starts from the latest snapshot taken at or before that time
+// **  and adds the entries since, so only a day of entries has to be summed.
+// ** Without a snapshot it falls back to the current balance minus everything booked since.
+// ** A snapshot is only right if no entry created before it committed after it was taken,
+// **  which is why the snapshot job waits before snapshotting a midnight (see snapshot.Job).
+func (store *SQLStore) GetBalanceAt(ctx context.Context, accountID int64, at time.Time) (int64, error) {
+	snapshot, err := store.GetLatestBalanceSnapshot(ctx, GetLatestBalanceSnapshotParams{
+		AccountID: accountID,
+		At:        at,
+	})
+	if err == ErrRecordNotFound {
+		return store.GetAccountBalanceBefore(ctx, GetAccountBalanceBeforeParams{
+			Before:    at,
+			AccountID: accountID,
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	since, err := store.SumAccountEntriesBetween(ctx, SumAccountEntriesBetweenParams{
+		AccountID: accountID,
+		FromTime:  snapshot.SnapshotAt,
+		ToTime:    at,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return snapshot.Balance + since, nil
+}
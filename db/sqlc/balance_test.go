@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test Get Balance At
+func TestGetBalanceAt(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t, testQueries)
+	account2 := createRandomAccountIn(t, testQueries, account1.Currency)
+
+	var entries []Entry
+	for i := 0; i < 4; i++ {
+		result, err := store.TransferTx(ctx, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        int64(i + 1),
+		})
+		require.NoError(t, err)
+		entries = append(entries, result.FromEntry)
+	}
+
+	times := []time.Time{
+		account1.CreatedAt,
+		entries[0].CreatedAt,
+		entries[1].CreatedAt.Add(time.Microsecond),
+		entries[2].CreatedAt,
+		entries[3].CreatedAt.Add(time.Microsecond),
+		entries[3].CreatedAt.Add(time.Hour),
+	}
+
+	// ** the balance at a time is the opening balance minus every transfer booked before it,
+	// **  summed here from the entries the transfers returned, not by another query
+	expectedAt := func(at time.Time) int64 {
+		balance := account1.Balance
+		for _, entry := range entries {
+			if entry.CreatedAt.Before(at) {
+				balance += entry.Amount
+			}
+		}
+		return balance
+	}
+
+	for _, withSnapshot := range []bool{false, true} {
+		if withSnapshot {
+			_, err := testQueries.CreateBalanceSnapshots(ctx, entries[1].CreatedAt)
+			require.NoError(t, err)
+
+			snapshot, err := testQueries.GetLatestBalanceSnapshot(ctx, GetLatestBalanceSnapshotParams{
+				AccountID: account1.ID,
+				At:        entries[3].CreatedAt,
+			})
+			require.NoError(t, err)
+			require.Equal(t, account1.Balance-1, snapshot.Balance)
+		}
+
+		for _, at := range times {
+			balance, err := store.GetBalanceAt(ctx, account1.ID, at)
+			require.NoError(t, err)
+			require.Equal(t, expectedAt(at), balance, "at %s, snapshot %t", at, withSnapshot)
+		}
+	}
+
+	balance, err := store.GetBalanceAt(ctx, account1.ID, entries[3].CreatedAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, balance)
+}
+
+// ** Test Create Balance Snapshots
+func TestCreateBalanceSnapshots(t *testing.T) {
+	account := createRandomAccount(t, testQueries)
+	snapshotAt := account.CreatedAt.Add(time.Second)
+
+	n, err := testQueries.CreateBalanceSnapshots(context.Background(), snapshotAt)
+	require.NoError(t, err)
+	require.NotZero(t, n)
+
+	// ** snapshots are only taken once per time
+	n, err = testQueries.CreateBalanceSnapshots(context.Background(), snapshotAt)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -0,0 +1,217 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ** The Store shadows every query it does not override elsewhere with one that classifies
+// **  its error (see ClassifyError). Everything that runs in execTx is classified there.
+// ** The reads routed to the read replica are in replica.go.
+
+func (store *SQLStore) AdvanceAuditChainHead(ctx context.Context, arg AdvanceAuditChainHeadParams) error {
+	return ClassifyError(store.Queries.AdvanceAuditChainHead(ctx, arg))
+}
+
+func (store *SQLStore) ClaimTask(ctx context.Context, leaseSeconds float64) (Task, error) {
+	return classify(store.Queries.ClaimTask(ctx, leaseSeconds))
+}
+
+func (store *SQLStore) CompleteTask(ctx context.Context, arg CompleteTaskParams) (int64, error) {
+	return classify(store.Queries.CompleteTask(ctx, arg))
+}
+
+func (store *SQLStore) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error) {
+	return classify(store.Queries.CreateAuditLog(ctx, arg))
+}
+
+func (store *SQLStore) CreateBalanceSnapshots(ctx context.Context, snapshotAt time.Time) (int64, error) {
+	return classify(store.Queries.CreateBalanceSnapshots(ctx, snapshotAt))
+}
+
+func (store *SQLStore) CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error) {
+	return classify(store.Queries.CreateInterestAccrual(ctx, arg))
+}
+
+func (store *SQLStore) CreateOverdraftUsage(ctx context.Context, arg CreateOverdraftUsageParams) (OverdraftUsage, error) {
+	return classify(store.Queries.CreateOverdraftUsage(ctx, arg))
+}
+
+func (store *SQLStore) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	return classify(store.Queries.CreatePasswordReset(ctx, arg))
+}
+
+func (store *SQLStore) CreatePaymentInstruction(ctx context.Context, arg CreatePaymentInstructionParams) (PaymentInstruction, error) {
+	return classify(store.Queries.CreatePaymentInstruction(ctx, arg))
+}
+
+func (store *SQLStore) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	return classify(store.Queries.CreateSession(ctx, arg))
+}
+
+func (store *SQLStore) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
+	return classify(store.Queries.CreateTask(ctx, arg))
+}
+
+func (store *SQLStore) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
+	return classify(store.Queries.CreateVerifyEmail(ctx, arg))
+}
+
+// ** stays on the primary: payments are booked to the account it finds
+func (store *SQLStore) GetAccountByIdentifier(ctx context.Context, arg GetAccountByIdentifierParams) (Account, error) {
+	return classify(store.Queries.GetAccountByIdentifier(ctx, arg))
+}
+
+func (store *SQLStore) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	return classify(store.Queries.GetAccountForUpdate(ctx, id))
+}
+
+func (store *SQLStore) GetAccountTransferLimit(ctx context.Context, accountID int64) (TransferLimit, error) {
+	return classify(store.Queries.GetAccountTransferLimit(ctx, accountID))
+}
+
+func (store *SQLStore) GetAccountTransferTotals(ctx context.Context, arg GetAccountTransferTotalsParams) (GetAccountTransferTotalsRow, error) {
+	return classify(store.Queries.GetAccountTransferTotals(ctx, arg))
+}
+
+func (store *SQLStore) GetAuditChainHeadForUpdate(ctx context.Context, chain int32) (AuditChainHead, error) {
+	return classify(store.Queries.GetAuditChainHeadForUpdate(ctx, chain))
+}
+
+func (store *SQLStore) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	return classify(store.Queries.GetCurrency(ctx, code))
+}
+
+func (store *SQLStore) GetEntry(ctx context.Context, id int64) (Entry, error) {
+	return classify(store.Queries.GetEntry(ctx, id))
+}
+
+func (store *SQLStore) GetFeeSchedule(ctx context.Context, id int64) (FeeSchedule, error) {
+	return classify(store.Queries.GetFeeSchedule(ctx, id))
+}
+
+func (store *SQLStore) GetInterestPlan(ctx context.Context, id int64) (InterestPlan, error) {
+	return classify(store.Queries.GetInterestPlan(ctx, id))
+}
+
+func (store *SQLStore) GetInterestPosting(ctx context.Context, arg GetInterestPostingParams) (InterestPosting, error) {
+	return classify(store.Queries.GetInterestPosting(ctx, arg))
+}
+
+func (store *SQLStore) GetLastAuditLog(ctx context.Context) (AuditLog, error) {
+	return classify(store.Queries.GetLastAuditLog(ctx))
+}
+
+func (store *SQLStore) GetOwnerTransferLimit(ctx context.Context, owner string) (TransferLimit, error) {
+	return classify(store.Queries.GetOwnerTransferLimit(ctx, owner))
+}
+
+func (store *SQLStore) GetOwnerTransferTotals(ctx context.Context, arg GetOwnerTransferTotalsParams) (GetOwnerTransferTotalsRow, error) {
+	return classify(store.Queries.GetOwnerTransferTotals(ctx, arg))
+}
+
+func (store *SQLStore) GetPasswordReset(ctx context.Context, id int64) (PasswordReset, error) {
+	return classify(store.Queries.GetPasswordReset(ctx, id))
+}
+
+func (store *SQLStore) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	return classify(store.Queries.GetSession(ctx, id))
+}
+
+func (store *SQLStore) GetTask(ctx context.Context, id int64) (Task, error) {
+	return classify(store.Queries.GetTask(ctx, id))
+}
+
+func (store *SQLStore) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
+	return classify(store.Queries.GetTransfer(ctx, id))
+}
+
+func (store *SQLStore) GetTransferReversal(ctx context.Context, transferID int64) (TransferReversal, error) {
+	return classify(store.Queries.GetTransferReversal(ctx, transferID))
+}
+
+func (store *SQLStore) GetUser(ctx context.Context, username string) (User, error) {
+	return classify(store.Queries.GetUser(ctx, username))
+}
+
+func (store *SQLStore) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	return classify(store.Queries.GetUserByEmail(ctx, email))
+}
+
+func (store *SQLStore) GetVerifyEmail(ctx context.Context, id int64) (VerifyEmail, error) {
+	return classify(store.Queries.GetVerifyEmail(ctx, id))
+}
+
+func (store *SQLStore) KillTask(ctx context.Context, arg KillTaskParams) (int64, error) {
+	return classify(store.Queries.KillTask(ctx, arg))
+}
+
+func (store *SQLStore) ListAuditChainHeads(ctx context.Context) ([]AuditChainHead, error) {
+	return classify(store.Queries.ListAuditChainHeads(ctx))
+}
+
+func (store *SQLStore) ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]AuditLog, error) {
+	return classify(store.Queries.ListAuditLog(ctx, arg))
+}
+
+func (store *SQLStore) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	return classify(store.Queries.ListCurrencies(ctx))
+}
+
+func (store *SQLStore) ListEnabledCurrencies(ctx context.Context) ([]Currency, error) {
+	return classify(store.Queries.ListEnabledCurrencies(ctx))
+}
+
+func (store *SQLStore) ListFeeSchedules(ctx context.Context, arg ListFeeSchedulesParams) ([]FeeSchedule, error) {
+	return classify(store.Queries.ListFeeSchedules(ctx, arg))
+}
+
+func (store *SQLStore) ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error) {
+	return classify(store.Queries.ListInterestAccruals(ctx, arg))
+}
+
+func (store *SQLStore) ListInterestBearingAccounts(ctx context.Context) ([]ListInterestBearingAccountsRow, error) {
+	return classify(store.Queries.ListInterestBearingAccounts(ctx))
+}
+
+func (store *SQLStore) ListUserSessions(ctx context.Context, username string) ([]Session, error) {
+	return classify(store.Queries.ListUserSessions(ctx, username))
+}
+
+func (store *SQLStore) LockOwnerTransfers(ctx context.Context, owner string) error {
+	return ClassifyError(store.Queries.LockOwnerTransfers(ctx, owner))
+}
+
+func (store *SQLStore) RetryTask(ctx context.Context, arg RetryTaskParams) (int64, error) {
+	return classify(store.Queries.RetryTask(ctx, arg))
+}
+
+func (store *SQLStore) SumInterestAccruals(ctx context.Context, arg SumInterestAccrualsParams) (int64, error) {
+	return classify(store.Queries.SumInterestAccruals(ctx, arg))
+}
+
+func (store *SQLStore) SumInterestAccrualsBefore(ctx context.Context, arg SumInterestAccrualsBeforeParams) (int64, error) {
+	return classify(store.Queries.SumInterestAccrualsBefore(ctx, arg))
+}
+
+func (store *SQLStore) SumInterestPostings(ctx context.Context, arg SumInterestPostingsParams) (SumInterestPostingsRow, error) {
+	return classify(store.Queries.SumInterestPostings(ctx, arg))
+}
+
+func (store *SQLStore) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	return classify(store.Queries.UpdateUserPassword(ctx, arg))
+}
+
+func (store *SQLStore) UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordReset, error) {
+	return classify(store.Queries.UsePasswordReset(ctx, arg))
+}
+
+func (store *SQLStore) UseVerifyEmail(ctx context.Context, arg UseVerifyEmailParams) (VerifyEmail, error) {
+	return classify(store.Queries.UseVerifyEmail(ctx, arg))
+}
+
+func (store *SQLStore) VerifyUserEmail(ctx context.Context, username string) (User, error) {
+	return classify(store.Queries.VerifyUserEmail(ctx, username))
+}
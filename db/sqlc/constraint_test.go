@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** requireConstraint checks that err is a violation of the named constraint
+func requireConstraint(t *testing.T, err error, kind error, constraint string) {
+	require.ErrorIs(t, err, kind)
+
+	var constraintErr *ConstraintError
+	require.ErrorAs(t, err, &constraintErr)
+	require.Equal(t, constraint, constraintErr.Constraint)
+}
+
+// ** Test the ledger constraints of the schema
+func TestLedgerConstraints(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t, testQueries)
+	account2 := createRandomAccountIn(t, testQueries, account1.Currency)
+
+	t.Run("EntryWithoutAccount", func(t *testing.T) {
+		_, err := testDB.Exec(ctx, "INSERT INTO entries (account_id, amount) VALUES (NULL, 10)")
+		err = ClassifyError(err)
+		require.ErrorIs(t, err, ErrNotNullViolation)
+
+		var constraintErr *ConstraintError
+		require.ErrorAs(t, err, &constraintErr)
+		require.Equal(t, "entries", constraintErr.Table)
+		require.Equal(t, "account_id", constraintErr.Column)
+	})
+
+	t.Run("TransferAmountNotPositive", func(t *testing.T) {
+		for _, amount := range []int64{0, -10} {
+			_, err := store.CreateTransfer(ctx, CreateTransferParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			})
+			requireConstraint(t, err, ErrCheckViolation, "transfers_amount_check")
+		}
+	})
+
+	t.Run("TransferToSameAccount", func(t *testing.T) {
+		_, err := store.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account1.ID,
+			Amount:        10,
+		})
+		requireConstraint(t, err, ErrCheckViolation, "transfers_accounts_check")
+	})
+
+	t.Run("NegativeBalance", func(t *testing.T) {
+		_, err := store.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     account1.ID,
+			Amount: -account1.Balance - 1,
+		})
+		requireConstraint(t, err, ErrCheckViolation, "accounts_balance_check")
+
+		// ** TransferTx refuses a transfer the balance does not cover before the schema has to
+		_, err = store.TransferTx(ctx, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        account1.Balance + 1,
+		})
+		require.ErrorIs(t, err, ErrInsufficientFunds)
+
+		updated, err := store.GetAccount(ctx, account1.ID)
+		require.NoError(t, err)
+		require.Equal(t, account1.Balance, updated.Balance)
+	})
+
+	t.Run("EntryOfUnknownTransfer", func(t *testing.T) {
+		_, err := store.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  account1.ID,
+			Amount:     10,
+			TransferID: sql.NullInt64{Int64: -1, Valid: true},
+		})
+		requireConstraint(t, err, ErrForeignKeyViolation, "entries_transfer_id_fkey")
+	})
+}
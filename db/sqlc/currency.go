@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ** Currency errors returned by the Store
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrCurrencyDisabled    = errors.New("currency is disabled")
+)
+
+// ** EnabledCurrency returns the currency with the given code when the bank accepts it for new business
+func (store *SQLStore) EnabledCurrency(ctx context.Context, code string) (Currency, error) {
+	currency, err := store.GetCurrency(ctx, code)
+	if err == ErrRecordNotFound {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, code)
+	}
+	if err != nil {
+		return Currency{}, err
+	}
+	if !currency.Enabled {
+		return Currency{}, fmt.Errorf("%w: %s", ErrCurrencyDisabled, code)
+	}
+	return currency, nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** Test List Currencies
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(currencies), len(money.Currencies()))
+
+	// ** the seeded exponents agree with the money package
+	for _, currency := range currencies {
+		if c, err := money.LookupCurrency(currency.Code); err == nil {
+			require.Equal(t, int32(c.Exponent), currency.Exponent, currency.Code)
+		}
+	}
+}
+
+// ** Test List Enabled Currencies
+func TestListEnabledCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListEnabledCurrencies(context.Background())
+	require.NoError(t, err)
+
+	codes := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		require.True(t, currency.Enabled)
+		codes = append(codes, currency.Code)
+	}
+	// ** every currency the tests open accounts in is enabled
+	for _, code := range []string{"EUR", "USD", "CAD", "TRY"} {
+		require.Contains(t, codes, code)
+	}
+}
+
+// ** Test Enabled Currency
+func TestEnabledCurrency(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	currency, err := store.EnabledCurrency(ctx, testRand.Currency())
+	require.NoError(t, err)
+	require.Equal(t, int32(2), currency.Exponent)
+
+	_, err = store.EnabledCurrency(ctx, "XYZ")
+	require.ErrorIs(t, err, ErrUnsupportedCurrency)
+
+	_, err = store.EnabledCurrency(ctx, "CHF")
+	require.ErrorIs(t, err, ErrCurrencyDisabled)
+
+	currency, err = testQueries.SetCurrencyEnabled(ctx, SetCurrencyEnabledParams{Code: "CHF", Enabled: true})
+	require.NoError(t, err)
+	require.True(t, currency.Enabled)
+	defer testQueries.SetCurrencyEnabled(ctx, SetCurrencyEnabledParams{Code: "CHF", Enabled: false})
+
+	_, err = store.EnabledCurrency(ctx, "CHF")
+	require.NoError(t, err)
+}
+
+// ** Test Create Account With Unknown Currency
+func TestCreateAccountUnknownCurrency(t *testing.T) {
+	_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    testRand.Owner(),
+		Currency: "XYZ",
+	})
+	require.Error(t, err)
+}
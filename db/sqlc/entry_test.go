@@ -2,23 +2,21 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
-	"github.com/techschool/simplebank/util"
 )
 
 // ** for testing we should create an account first
-func createRandomAccountForEntry(t *testing.T) Account {
+func createRandomAccountForEntry(t *testing.T, q *Queries) Account {
 	arg := CreateAccountParams{
-		Owner:    util.RandomOwner(),
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Owner:    testRand.Owner(),
+		Balance:  testRand.Money(),
+		Currency: testRand.Currency(),
 	}
 
-	account, error := testQueries.CreateAccount(context.Background(), arg)
+	account, error := q.CreateAccount(context.Background(), arg)
 	require.NoError(t, error)
 	require.NotEmpty(t, account)
 
@@ -34,13 +32,19 @@ func createRandomAccountForEntry(t *testing.T) Account {
 
 // ** test create account for entry
 func TestCreateAccountForEntry(t *testing.T) {
-	createRandomAccountForEntry(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	createRandomAccountForEntry(t, q)
 }
 
 // ** test get account for entry
 func TestGetAccountForEntry(t *testing.T) {
-	account1 := createRandomAccountForEntry(t)
-	account2, error := testQueries.GetAccount(context.Background(), account1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account1 := createRandomAccountForEntry(t, q)
+	account2, error := q.GetAccount(context.Background(), account1.ID)
 	require.NoError(t, error)
 	require.NotEmpty(t, account2)
 
@@ -53,14 +57,14 @@ func TestGetAccountForEntry(t *testing.T) {
 }
 
 // ** create random entry
-func createRandomEntry(t *testing.T) Entry {
-	account1 := createRandomAccountForEntry(t)
+func createRandomEntry(t *testing.T, q *Queries) Entry {
+	account1 := createRandomAccountForEntry(t, q)
 	arg := CreateEntryParams{
 		AccountID: account1.ID,
-		Amount:    util.RandomAmount(),
+		Amount:    testRand.Amount(),
 	}
 
-	entry, error := testQueries.CreateEntry(context.Background(), arg)
+	entry, error := q.CreateEntry(context.Background(), arg)
 	require.NoError(t, error)
 	require.NotEmpty(t, entry)
 
@@ -75,13 +79,19 @@ func createRandomEntry(t *testing.T) Entry {
 
 // ** test create entry
 func TestCreateEntry(t *testing.T) {
-	createRandomEntry(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	createRandomEntry(t, q)
 }
 
 // ** test get entry
 func TestGetEntry(t *testing.T) {
-	entry1 := createRandomEntry(t)
-	entry2, error := testQueries.GetEntry(context.Background(), entry1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	entry1 := createRandomEntry(t, q)
+	entry2, error := q.GetEntry(context.Background(), entry1.ID)
 	require.NoError(t, error)
 	require.NotEmpty(t, entry2)
 
@@ -93,13 +103,16 @@ func TestGetEntry(t *testing.T) {
 
 // ** test update entry
 func TestUpdateEntry(t *testing.T) {
-	entry1 := createRandomEntry(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	entry1 := createRandomEntry(t, q)
 	arg := UpdateEntryParams{
 		ID:     entry1.ID,
-		Amount: util.RandomAmount(),
+		Amount: testRand.Amount(),
 	}
 
-	entry2, error := testQueries.UpdateEntry(context.Background(), arg)
+	entry2, error := q.UpdateEntry(context.Background(), arg)
 	require.NoError(t, error)
 	require.NotEmpty(t, entry2)
 
@@ -112,22 +125,28 @@ func TestUpdateEntry(t *testing.T) {
 
 // ** test delete entry
 func TestDeleteEntry(t *testing.T) {
-	entry1 := createRandomEntry(t)
-	error := testQueries.DeleteEntry(context.Background(), entry1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	entry1 := createRandomEntry(t, q)
+	error := q.DeleteEntry(context.Background(), entry1.ID)
 	require.NoError(t, error)
 
 	// ** is entry really deleted?
-	entry2, error2 := testQueries.GetEntry(context.Background(), entry1.ID)
+	entry2, error2 := q.GetEntry(context.Background(), entry1.ID)
 	require.Error(t, error2)
-	require.EqualError(t, error2, sql.ErrNoRows.Error())
+	require.EqualError(t, error2, ErrRecordNotFound.Error())
 	require.Empty(t, entry2)
 }
 
 // ** test list entries
 func TestListEntries(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
 	// ** create 10 random entries
 	for i := 0; i < 10; i++ {
-		createRandomEntry(t)
+		createRandomEntry(t, q)
 	}
 
 	arg := ListEntriesParams{ // ** skip the first 3 records and return the next 5
@@ -136,7 +155,7 @@ func TestListEntries(t *testing.T) {
 	}
 
 	// ** when we run the test, there will be at least 10 entries in the database, so with these parameters we expect to get 5 records
-	accounts, error := testQueries.ListEntries(context.Background(), arg)
+	accounts, error := q.ListEntries(context.Background(), arg)
 	require.NoError(t, error)
 	require.Len(t, accounts, 5)
 
@@ -145,3 +164,50 @@ func TestListEntries(t *testing.T) {
 	}
 
 }
+
+// ** test list account entries between
+func TestListAccountEntriesBetween(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account := createRandomAccountForEntry(t, q)
+	from := time.Now().Add(-time.Minute)
+
+	var total int64
+	for i := 0; i < 3; i++ {
+		entry, error := q.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    testRand.Amount(),
+		})
+		require.NoError(t, error)
+		total += entry.Amount
+	}
+
+	entries, error := q.ListAccountEntriesBetween(context.Background(), ListAccountEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    time.Now().Add(time.Minute),
+	})
+	require.NoError(t, error)
+	require.Len(t, entries, 3)
+
+	for _, entry := range entries {
+		require.Equal(t, account.ID, entry.AccountID)
+	}
+
+	// ** nothing is booked before the period
+	entries, error = q.ListAccountEntriesBetween(context.Background(), ListAccountEntriesBetweenParams{
+		AccountID: account.ID,
+		FromTime:  from.Add(-time.Hour),
+		ToTime:    from,
+	})
+	require.NoError(t, error)
+	require.Empty(t, entries)
+
+	sum, error := q.SumAccountEntriesSince(context.Background(), SumAccountEntriesSinceParams{
+		AccountID: account.ID,
+		Since:     from,
+	})
+	require.NoError(t, error)
+	require.Equal(t, total, sum)
+}
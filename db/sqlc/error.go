@@ -0,0 +1,87 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ** ErrRecordNotFound is returned when a query finds no row. It is pgx.ErrNoRows itself,
+// **  so callers comparing with either keep working.
+var ErrRecordNotFound = pgx.ErrNoRows
+
+// ** Errors of violated database constraints, wrapped in a ConstraintError
+var (
+	ErrUniqueViolation     = errors.New("unique violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrCheckViolation      = errors.New("check violation")
+	ErrNotNullViolation    = errors.New("not null violation")
+)
+
+// ** constraintViolations maps SQLSTATE codes to the errors above
+var constraintViolations = map[string]error{
+	"23502": ErrNotNullViolation,
+	"23503": ErrForeignKeyViolation,
+	"23505": ErrUniqueViolation,
+	"23514": ErrCheckViolation,
+}
+
+// ** ConstraintError tells which constraint a statement violated.
+// ** errors.Is matches its Kind, errors.As still reaches the *pgconn.PgError.
+type ConstraintError struct {
+	Kind       error
+	Constraint string
+	Table      string
+	Column     string
+	Detail     string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	name := e.Constraint
+	if name == "" {
+		name = e.Table + "." + e.Column
+	}
+	if e.Detail == "" {
+		return fmt.Sprintf("%v on %s", e.Kind, name)
+	}
+	return fmt.Sprintf("%v on %s: %s", e.Kind, name, e.Detail)
+}
+
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// ** ClassifyError translates constraint violations raised through pgx into a
+// **  *ConstraintError. Any other error is returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var constraintErr *ConstraintError
+	if errors.As(err, &constraintErr) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if kind, ok := constraintViolations[pgErr.Code]; ok {
+			return &ConstraintError{
+				Kind:       kind,
+				Constraint: pgErr.ConstraintName,
+				Table:      pgErr.TableName,
+				Column:     pgErr.ColumnName,
+				Detail:     pgErr.Detail,
+				Err:        err,
+			}
+		}
+	}
+	return err
+}
+
+// ** classify returns the row of a query together with its classified error
+func classify[T any](row T, err error) (T, error) {
+	return row, ClassifyError(err)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test Classify Error
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		kind       error
+		constraint string
+	}{
+		{"pgx unique", &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}, ErrUniqueViolation, "users_email_key"},
+		{"pgx foreign key", fmt.Errorf("create entry: %w", &pgconn.PgError{Code: "23503", ConstraintName: "entries_account_id_fkey"}), ErrForeignKeyViolation, "entries_account_id_fkey"},
+		{"pgx check", &pgconn.PgError{Code: "23514", ConstraintName: "transfer_limits_check"}, ErrCheckViolation, "transfer_limits_check"},
+		{"pgx not null", &pgconn.PgError{Code: "23502", TableName: "accounts", ColumnName: "owner"}, ErrNotNullViolation, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ClassifyError(tc.err)
+			require.ErrorIs(t, err, tc.kind)
+
+			var constraintErr *ConstraintError
+			require.ErrorAs(t, err, &constraintErr)
+			require.Equal(t, tc.constraint, constraintErr.Constraint)
+
+			// ** the driver error stays reachable, and classifying twice changes nothing
+			require.True(t, errors.As(err, new(*pgconn.PgError)))
+			require.Equal(t, err, ClassifyError(err))
+		})
+	}
+}
+
+// ** Test Classify Error passes other errors through unchanged
+func TestClassifyErrorUnchanged(t *testing.T) {
+	require.NoError(t, ClassifyError(nil))
+	require.True(t, ClassifyError(ErrRecordNotFound) == ErrRecordNotFound)
+
+	connErr := &pgconn.PgError{Code: "57P01"}
+	require.Equal(t, connErr, ClassifyError(connErr))
+	require.Equal(t, pgx.ErrTxClosed, ClassifyError(pgx.ErrTxClosed))
+}
+
+// ** Test Constraint Error message
+func TestConstraintErrorMessage(t *testing.T) {
+	err := ClassifyError(&pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", Detail: "Key (email)=(a@b.com) already exists."})
+	require.EqualError(t, err, "unique violation on users_email_key: Key (email)=(a@b.com) already exists.")
+
+	err = ClassifyError(&pgconn.PgError{Code: "23502", TableName: "accounts", ColumnName: "owner"})
+	require.EqualError(t, err, "not null violation on accounts.owner")
+}
+
+// ** Test the Store classifies the errors of queries and transactions
+func TestStoreClassifiesErrors(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t, testQueries)
+
+	_, err := store.CreateUser(context.Background(), CreateUserParams{
+		Username:       testRand.Owner(),
+		HashedPassword: user.HashedPassword,
+		FullName:       user.FullName,
+		Email:          user.Email,
+	})
+	require.ErrorIs(t, err, ErrUniqueViolation)
+	var constraintErr *ConstraintError
+	require.ErrorAs(t, err, &constraintErr)
+	require.Equal(t, "users_email_key", constraintErr.Constraint)
+
+	_, err = store.CreateEntry(context.Background(), CreateEntryParams{AccountID: -1, Amount: 10})
+	require.ErrorIs(t, err, ErrForeignKeyViolation)
+	require.ErrorAs(t, err, &constraintErr)
+	require.Equal(t, "entries_account_id_fkey", constraintErr.Constraint)
+
+	_, err = store.GetAccount(context.Background(), -1)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// ** ErrFeeCurrencyMismatch is returned when a fee schedule is used for an account in another currency
+var ErrFeeCurrencyMismatch = errors.New("fee schedule currency does not match the account")
+
+const basisPoints = 10000
+
+// ** Compute returns the fee for a transfer of amount.
+// ** transfersThisMonth counts the sender's transfers this month, including this one;
+// **  the fixed and percentage fees are only charged once the free tier is used up.
+// ** It fails with money.ErrOverflow when the fee does not fit in an int64.
+func (s FeeSchedule) Compute(amount int64, crossCurrency bool, instant bool, transfersThisMonth int64) (int64, error) {
+	transferred := money.Money{Amount: amount, Currency: s.Currency}
+	var parts []money.Money
+	if transfersThisMonth > s.FreeTransfersPerMonth {
+		percentage, err := bps(transferred, s.PercentageBps)
+		if err != nil {
+			return 0, err
+		}
+		parts = append(parts, money.Money{Amount: s.FixedFee, Currency: s.Currency}, percentage)
+	}
+	if crossCurrency {
+		crossCurrencyFee, err := bps(transferred, s.CrossCurrencyBps)
+		if err != nil {
+			return 0, err
+		}
+		parts = append(parts, crossCurrencyFee)
+	}
+	if instant {
+		parts = append(parts, money.Money{Amount: s.InstantFee, Currency: s.Currency})
+	}
+
+	fee := money.Money{Currency: s.Currency}
+	for _, part := range parts {
+		var err error
+		if fee, err = fee.Add(part); err != nil {
+			return 0, err
+		}
+	}
+
+	if fee.Amount > 0 && fee.Amount < s.MinFee {
+		fee.Amount = s.MinFee
+	}
+	if s.MaxFee.Valid && fee.Amount > s.MaxFee.Int64 {
+		fee.Amount = s.MaxFee.Int64
+	}
+	return fee.Amount, nil
+}
+
+// ** bps returns rate basis points of amount, rounded half up
+func bps(amount money.Money, rate int64) (money.Money, error) {
+	product, err := amount.Mul(rate)
+	if err != nil {
+		return money.Money{}, err
+	}
+	product, err = product.Add(money.Money{Amount: basisPoints / 2, Currency: amount.Currency})
+	if err != nil {
+		return money.Money{}, err
+	}
+	return money.Money{Amount: product.Amount / basisPoints, Currency: amount.Currency}, nil
+}
+
+// ** computeFee evaluates the schedule for a transfer that has already been recorded.
+// ** The schedule, the sender and the fee account must all hold the same currency.
+func computeFee(ctx context.Context, q *Queries, schedule FeeSchedule, from Account, to Account, feeAccount Account, arg TransferTxParams) (int64, error) {
+	for _, account := range []Account{from, feeAccount} {
+		if account.Currency != schedule.Currency {
+			return 0, fmt.Errorf("%w: schedule %s is in %s, account %d in %s", ErrFeeCurrencyMismatch, schedule.Name, schedule.Currency, account.ID, account.Currency)
+		}
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	totals, err := q.GetAccountTransferTotals(ctx, GetAccountTransferTotalsParams{
+		AccountID: from.ID,
+		Since:     monthStart,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return schedule.Compute(arg.Amount, from.Currency != to.Currency, arg.Instant, totals.Count)
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+)
+
+func createRandomFeeSchedule(t *testing.T, q *Queries, feeAccount Account) FeeSchedule {
+	arg := CreateFeeScheduleParams{
+		Name:                  testRand.String(10),
+		Currency:              feeAccount.Currency,
+		FeeAccountID:          feeAccount.ID,
+		FixedFee:              25,
+		PercentageBps:         100,
+		CrossCurrencyBps:      50,
+		InstantFee:            200,
+		MinFee:                10,
+		MaxFee:                sql.NullInt64{Int64: 1000, Valid: true},
+		FreeTransfersPerMonth: 1,
+	}
+
+	schedule, err := q.CreateFeeSchedule(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, schedule.ID)
+	require.Equal(t, arg.Name, schedule.Name)
+	require.Equal(t, arg.FeeAccountID, schedule.FeeAccountID)
+	require.Equal(t, arg.MaxFee, schedule.MaxFee)
+	require.NotZero(t, schedule.CreatedAt)
+
+	return schedule
+}
+
+// ** Test Fee Schedule Compute
+func TestFeeScheduleCompute(t *testing.T) {
+	schedule := FeeSchedule{
+		FixedFee:              25,
+		PercentageBps:         100,
+		CrossCurrencyBps:      50,
+		InstantFee:            200,
+		MinFee:                40,
+		MaxFee:                sql.NullInt64{Int64: 1000, Valid: true},
+		FreeTransfersPerMonth: 2,
+	}
+
+	compute := func(amount int64, crossCurrency bool, instant bool, transfersThisMonth int64) int64 {
+		fee, err := schedule.Compute(amount, crossCurrency, instant, transfersThisMonth)
+		require.NoError(t, err)
+		return fee
+	}
+
+	// ** within the free tier nothing is charged
+	require.Zero(t, compute(10000, false, false, 2))
+	// ** 25 + 1% of 10000
+	require.Equal(t, int64(125), compute(10000, false, false, 3))
+	// ** instant and cross currency fees also apply within the free tier
+	require.Equal(t, int64(250), compute(10000, true, true, 1))
+	// ** 25 + 1% of 150 rounded half up is below the minimum
+	require.Equal(t, int64(40), compute(150, false, false, 3))
+	// ** capped
+	require.Equal(t, int64(1000), compute(1000000, true, false, 3))
+
+	// ** a percentage of a huge amount overflows instead of wrapping around
+	_, err := schedule.Compute(math.MaxInt64/50, false, false, 3)
+	require.ErrorIs(t, err, money.ErrOverflow)
+}
+
+// ** Test Get Fee Schedule
+func TestGetFeeSchedule(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	schedule1 := createRandomFeeSchedule(t, q, createRandomAccount(t, q))
+
+	schedule2, err := q.GetFeeSchedule(context.Background(), schedule1.ID)
+	require.NoError(t, err)
+	require.Equal(t, schedule1, schedule2)
+
+	schedules, err := q.ListFeeSchedules(context.Background(), ListFeeSchedulesParams{Limit: 5})
+	require.NoError(t, err)
+	require.NotEmpty(t, schedules)
+
+	require.NoError(t, q.DeleteFeeSchedule(context.Background(), schedule1.ID))
+	_, err = q.GetFeeSchedule(context.Background(), schedule1.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+// ** Test Transfer Tx With Fee
+func TestTransferTxWithFee(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    testRand.Owner(),
+		Balance:  testRand.Money(),
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	houseAccount, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    "house",
+		Balance:  0,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	schedule := createRandomFeeSchedule(t, q, houseAccount)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		FeeScheduleID: schedule.ID,
+	}
+
+	// ** the first transfer of the month is free
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, result.Fee)
+	require.Empty(t, result.FeeEntries)
+
+	result, err = store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	expected, err := schedule.Compute(arg.Amount, false, false, 2)
+	require.NoError(t, err)
+	require.Equal(t, expected, result.Fee)
+	require.Len(t, result.FeeEntries, 2)
+	require.Equal(t, account1.ID, result.FeeEntries[0].AccountID)
+	require.Equal(t, -expected, result.FeeEntries[0].Amount)
+	require.Equal(t, houseAccount.ID, result.FeeEntries[1].AccountID)
+	require.Equal(t, expected, result.FeeEntries[1].Amount)
+	for _, entry := range result.FeeEntries {
+		require.Equal(t, sql.NullInt64{Int64: result.Transfer.ID, Valid: true}, entry.TransferID)
+	}
+
+	require.Equal(t, account1.Balance-2*arg.Amount-expected, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+2*arg.Amount, result.ToAccount.Balance)
+
+	updatedHouse, err := store.GetAccount(context.Background(), houseAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, expected, updatedHouse.Balance)
+}
+
+// ** Test Transfer Tx Fee Currency Mismatch
+func TestTransferTxFeeCurrencyMismatch(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+	houseAccount := createRandomAccount(t, q)
+	schedule := createRandomFeeSchedule(t, q, houseAccount)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		FeeScheduleID: schedule.ID,
+		Instant:       true,
+	})
+	if account1.Currency == schedule.Currency {
+		require.NoError(t, err)
+	} else {
+		require.ErrorIs(t, err, ErrFeeCurrencyMismatch)
+	}
+}
+
+// ** Test Transfer Tx with a fee account in another currency than its schedule
+func TestTransferTxFeeAccountCurrencyMismatch(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccountIn(t, q, "EUR")
+	account2 := createRandomAccountIn(t, q, "EUR")
+	houseAccount := createRandomAccountIn(t, q, "USD")
+	schedule, err := q.CreateFeeSchedule(context.Background(), CreateFeeScheduleParams{
+		Name:         testRand.String(10),
+		Currency:     "EUR",
+		FeeAccountID: houseAccount.ID,
+		InstantFee:   200,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		FeeScheduleID: schedule.ID,
+		Instant:       true,
+	})
+	require.ErrorIs(t, err, ErrFeeCurrencyMismatch)
+
+	// ** nothing was booked
+	updated, err := store.GetAccount(context.Background(), houseAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, houseAccount.Balance, updated.Balance)
+}
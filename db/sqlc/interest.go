@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// ** accruals are kept in millionths of a minor unit, posting only whole minor units
+const interestMicros = 1000000
+
+// ** errInterestAlreadyPosted rolls back a posting that lost the race for its period
+var errInterestAlreadyPosted = errors.New("interest already posted")
+
+// ** PostInterestTxParams contains the input parameters of the interest posting transaction
+type PostInterestTxParams struct {
+	AccountID         int64     `json:"account_id"`
+	InterestAccountID int64     `json:"interest_account_id"`
+	PeriodStart       time.Time `json:"period_start"`
+	PeriodEnd         time.Time `json:"period_end"`
+}
+
+// ** PostInterestTxResult is the result of the interest posting transaction
+type PostInterestTxResult struct {
+	Posting       InterestPosting `json:"posting"`
+	AlreadyPosted bool            `json:"already_posted"`
+	Account       Account         `json:"account"`
+	Entry         Entry           `json:"entry"`
+	HouseEntry    Entry           `json:"house_entry"`
+}
+
+// ** PostInterestTx books the interest accrued over [PeriodStart, PeriodEnd) as entries
+// **  from the house interest account to the account. Overdraft interest accrues negative,
+// **  so it is booked the other way. Both accounts must be active and hold the same currency,
+// **  and the paying side must have the funds, otherwise nothing is posted.
+// ** Only whole minor units are posted: the remaining fraction stays accrued and is posted with a later period.
+// ** A period is posted at most once: posting it again returns the existing posting with AlreadyPosted set.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// ** a period already posted is returned as is, before any account is checked or locked
+		_, err := q.GetInterestPosting(ctx, GetInterestPostingParams{
+			AccountID:   arg.AccountID,
+			PeriodStart: arg.PeriodStart,
+		})
+		if err == nil {
+			return errInterestAlreadyPosted
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		// ** post what has accrued up to the period end minus what was already posted,
+		// **  so the fraction left over by rounding is carried into the next posting
+		accrued, err := q.SumInterestAccrualsBefore(ctx, SumInterestAccrualsBeforeParams{
+			AccountID: arg.AccountID,
+			Before:    arg.PeriodEnd,
+		})
+		if err != nil {
+			return err
+		}
+		posted, err := q.SumInterestPostings(ctx, SumInterestPostingsParams{
+			AccountID: arg.AccountID,
+			After:     arg.PeriodStart,
+		})
+		if err != nil {
+			return err
+		}
+		var amount int64
+		// ** a later period posted first has already swept up this one
+		if posted.LaterPeriods == 0 {
+			amount = accrued/interestMicros - posted.Total
+		}
+
+		var entryID sql.NullInt64
+		if amount != 0 {
+			accounts, err := lockAccounts(ctx, q, arg.InterestAccountID, arg.AccountID)
+			if err != nil {
+				return err
+			}
+			house := accounts[arg.InterestAccountID]
+			result.Account = accounts[arg.AccountID]
+			for _, account := range []Account{house, result.Account} {
+				if err = checkAccountActive(account); err != nil {
+					return err
+				}
+			}
+			if house.Currency != result.Account.Currency {
+				return fmt.Errorf("%w: house account %d holds %s, account %d %s", money.ErrCurrencyMismatch, house.ID, house.Currency, result.Account.ID, result.Account.Currency)
+			}
+			// ** whoever pays must have the funds: the house for interest, the account for overdraft interest
+			if amount > 0 {
+				err = checkFunds(house, amount)
+			} else {
+				err = checkFunds(result.Account, -amount)
+			}
+			if err != nil {
+				return err
+			}
+
+			result.HouseEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+				AccountID: arg.InterestAccountID,
+				Amount:    -amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+				AccountID: arg.AccountID,
+				Amount:    amount,
+			})
+			if err != nil {
+				return err
+			}
+			entryID = sql.NullInt64{Int64: result.Entry.ID, Valid: true}
+
+			_, result.Account, err = addMoney(ctx, q, arg.InterestAccountID, -amount, arg.AccountID, amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		// ** the unique (account_id, period_start) index makes a concurrent or repeated posting conflict here
+		result.Posting, err = q.CreateInterestPosting(ctx, CreateInterestPostingParams{
+			AccountID:   arg.AccountID,
+			PeriodStart: arg.PeriodStart,
+			PeriodEnd:   arg.PeriodEnd,
+			Amount:      amount,
+			EntryID:     entryID,
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return errInterestAlreadyPosted
+		}
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "interest.post", auditChange{
+			Entity:   "interest_posting",
+			EntityID: result.Posting.ID,
+			After:    result,
+		})
+	})
+
+	if errors.Is(err, errInterestAlreadyPosted) {
+		posting, err := store.GetInterestPosting(ctx, GetInterestPostingParams{
+			AccountID:   arg.AccountID,
+			PeriodStart: arg.PeriodStart,
+		})
+		return PostInterestTxResult{Posting: posting, AlreadyPosted: true}, err
+	}
+	return result, err
+}
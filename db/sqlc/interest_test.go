@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+)
+
+func createRandomInterestPlan(t *testing.T, q *Queries, interestAccount Account) InterestPlan {
+	arg := CreateInterestPlanParams{
+		Name:              testRand.String(10),
+		AnnualRateBps:     testRand.Int(1, 500),
+		OverdraftRateBps:  testRand.Int(500, 2000),
+		DayCount:          "ACT/365",
+		InterestAccountID: interestAccount.ID,
+	}
+
+	plan, err := q.CreateInterestPlan(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, plan.ID)
+	require.Equal(t, arg.Name, plan.Name)
+	require.Equal(t, arg.AnnualRateBps, plan.AnnualRateBps)
+	require.Equal(t, arg.OverdraftRateBps, plan.OverdraftRateBps)
+	require.Equal(t, arg.DayCount, plan.DayCount)
+	require.Equal(t, arg.InterestAccountID, plan.InterestAccountID)
+
+	return plan
+}
+
+// ** Test Attach Interest Plan
+func TestAttachInterestPlan(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account := createRandomAccount(t, q)
+	plan := createRandomInterestPlan(t, q, createRandomAccount(t, q))
+
+	attached, err := q.AttachInterestPlan(context.Background(), AttachInterestPlanParams{
+		AccountID:      account.ID,
+		InterestPlanID: plan.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, attached.AccountID)
+	require.Equal(t, plan.ID, attached.InterestPlanID)
+
+	accounts, err := q.ListInterestBearingAccounts(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, accounts, ListInterestBearingAccountsRow{
+		AccountID:         account.ID,
+		InterestPlanID:    plan.ID,
+		AnnualRateBps:     plan.AnnualRateBps,
+		OverdraftRateBps:  plan.OverdraftRateBps,
+		DayCount:          plan.DayCount,
+		InterestAccountID: plan.InterestAccountID,
+		Status:            AccountStatusActive,
+	})
+
+	require.NoError(t, q.DetachInterestPlan(context.Background(), account.ID))
+}
+
+// ** Test Post Interest Tx
+func TestPostInterestTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account := createRandomAccount(t, q)
+	house := createRandomAccountIn(t, q, account.Currency)
+	plan := createRandomInterestPlan(t, q, house)
+
+	periodStart := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	// ** 3 days of 0.4 minor units each, plus one day outside of the period
+	for _, day := range []int{1, 2, 31, 32} {
+		n, err := store.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+			AccountID:      account.ID,
+			InterestPlanID: plan.ID,
+			AccrualDate:    periodStart.AddDate(0, 0, day-1),
+			Balance:        account.Balance,
+			AmountMicros:   400000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	}
+
+	// ** accruals are unique per day
+	n, err := store.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+		AccountID:      account.ID,
+		InterestPlanID: plan.ID,
+		AccrualDate:    periodStart,
+		AmountMicros:   400000,
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	arg := PostInterestTxParams{
+		AccountID:         account.ID,
+		InterestAccountID: house.ID,
+		PeriodStart:       periodStart,
+		PeriodEnd:         periodEnd,
+	}
+
+	result, err := store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.AlreadyPosted)
+
+	// ** 1.2 minor units accrued, rounded down to 1
+	require.Equal(t, int64(1), result.Posting.Amount)
+	require.Equal(t, result.Entry.ID, result.Posting.EntryID.Int64)
+	require.Equal(t, int64(1), result.Entry.Amount)
+	require.Equal(t, int64(-1), result.HouseEntry.Amount)
+	require.Equal(t, account.Balance+1, result.Account.Balance)
+
+	// ** posting the same period again does not book anything
+	again, err := store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, again.AlreadyPosted)
+	require.Equal(t, result.Posting.ID, again.Posting.ID)
+
+	updatedAccount, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+1, updatedAccount.Balance)
+
+	// ** nor does it check an account frozen since
+	_, err = store.FreezeAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	again, err = store.PostInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, again.AlreadyPosted)
+	require.Equal(t, result.Posting.ID, again.Posting.ID)
+}
+
+// ** Test Post Interest Tx carrying the remainder into the next period
+func TestPostInterestTxCarriesRemainder(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account := createRandomAccount(t, q)
+	house := createRandomAccountIn(t, q, account.Currency)
+	plan := createRandomInterestPlan(t, q, house)
+	march := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	post := func(periodStart time.Time, amountMicros int64) PostInterestTxResult {
+		_, err := store.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:      account.ID,
+			InterestPlanID: plan.ID,
+			AccrualDate:    periodStart,
+			Balance:        account.Balance,
+			AmountMicros:   amountMicros,
+		})
+		require.NoError(t, err)
+
+		result, err := store.PostInterestTx(ctx, PostInterestTxParams{
+			AccountID:         account.ID,
+			InterestAccountID: house.ID,
+			PeriodStart:       periodStart,
+			PeriodEnd:         periodStart.AddDate(0, 1, 0),
+		})
+		require.NoError(t, err)
+		require.False(t, result.AlreadyPosted)
+		return result
+	}
+
+	// ** 1.2 minor units post 1, leaving 0.2
+	require.Equal(t, int64(1), post(march, 1200000).Posting.Amount)
+	// ** 0.2 + 0.7 is still short of a minor unit
+	april := post(march.AddDate(0, 1, 0), 700000)
+	require.Zero(t, april.Posting.Amount)
+	require.False(t, april.Posting.EntryID.Valid)
+	// ** 0.9 + 0.3 posts 1, leaving 0.2 again
+	require.Equal(t, int64(1), post(march.AddDate(0, 2, 0), 300000).Posting.Amount)
+
+	updatedAccount, err := store.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+2, updatedAccount.Balance)
+}
+
+// ** Test Post Interest Tx refusing what cannot be posted
+func TestPostInterestTxRefused(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+	periodStart := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	// ** posts 2 minor units of interest from house to account
+	post := func(account, house Account) error {
+		plan := createRandomInterestPlan(t, q, house)
+		_, err := store.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:      account.ID,
+			InterestPlanID: plan.ID,
+			AccrualDate:    periodStart,
+			Balance:        account.Balance,
+			AmountMicros:   2000000,
+		})
+		require.NoError(t, err)
+
+		_, err = store.PostInterestTx(ctx, PostInterestTxParams{
+			AccountID:         account.ID,
+			InterestAccountID: house.ID,
+			PeriodStart:       periodStart,
+			PeriodEnd:         periodStart.AddDate(0, 1, 0),
+		})
+		return err
+	}
+
+	account := createRandomAccountIn(t, q, "EUR")
+	emptyHouse, err := q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    "house",
+		Balance:  1,
+		Currency: "EUR",
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, post(account, emptyHouse), ErrInsufficientFunds)
+
+	require.ErrorIs(t, post(createRandomAccountIn(t, q, "EUR"), createRandomAccountIn(t, q, "USD")), money.ErrCurrencyMismatch)
+
+	frozen := createRandomAccountIn(t, q, "EUR")
+	_, err = store.FreezeAccount(ctx, frozen.ID)
+	require.NoError(t, err)
+	require.ErrorIs(t, post(frozen, createRandomAccountIn(t, q, "EUR")), ErrAccountFrozen)
+
+	// ** nothing was posted, so each period can still be posted later
+	for _, id := range []int64{account.ID, frozen.ID} {
+		_, err = store.GetInterestPosting(ctx, GetInterestPostingParams{AccountID: id, PeriodStart: periodStart})
+		require.ErrorIs(t, err, ErrRecordNotFound)
+	}
+}
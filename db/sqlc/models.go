@@ -5,15 +5,220 @@
 package db
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccountStatus struct {
+	AccountStatus AccountStatus
+	Valid         bool // Valid is true if AccountStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountStatus), nil
+}
+
+type UserRole string
+
+const (
+	UserRoleCustomer UserRole = "customer"
+	UserRoleBanker   UserRole = "banker"
+	UserRoleAdmin    UserRole = "admin"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type NullUserRole struct {
+	UserRole UserRole
+	Valid    bool // Valid is true if UserRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserRole), nil
+}
+
+type TaskStatus string
+
+const (
+	TaskStatusPending TaskStatus = "pending"
+	TaskStatusRunning TaskStatus = "running"
+	TaskStatusDone    TaskStatus = "done"
+	TaskStatusDead    TaskStatus = "dead"
 )
 
+func (e *TaskStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TaskStatus(s)
+	case string:
+		*e = TaskStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TaskStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTaskStatus struct {
+	TaskStatus TaskStatus
+	Valid      bool // Valid is true if TaskStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTaskStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TaskStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TaskStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTaskStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TaskStatus), nil
+}
+
 type Account struct {
 	ID        int64
 	Owner     string
 	Balance   int64
 	Currency  string
 	CreatedAt time.Time
+	Status    AccountStatus
+	// incremented on every change, for optimistic concurrency control
+	Version int64
+	// how far the balance may go below zero
+	OverdraftLimit int64
+}
+
+type AccountBalanceSnapshot struct {
+	AccountID  int64
+	SnapshotAt time.Time
+	// balance including every entry created before snapshot_at
+	Balance   int64
+	CreatedAt time.Time
+}
+
+type AccountIdentifier struct {
+	// IBAN, or OTHR for a proprietary identifier
+	Scheme     string
+	Identifier string
+	AccountID  int64
+	CreatedAt  time.Time
+}
+
+type AccountInterestPlan struct {
+	AccountID      int64
+	InterestPlanID int64
+	AttachedAt     time.Time
+}
+
+type AccountStatement struct {
+	ID        int64
+	AccountID int64
+	// sequence number of the statement within its account, starting at 1
+	Number      int64
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	CreatedAt   time.Time
+}
+
+type AuditChainHead struct {
+	Chain int32
+	// hash of the last row of the chain, empty while it has none
+	Hash     string
+	RowCount int64
+}
+
+type AuditLog struct {
+	ID        int64
+	Actor     string
+	RequestID string
+	Action    string
+	Entity    string
+	EntityID  int64
+	// json keeps the exact text that was hashed, unlike jsonb
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+	// hash of the previous row, empty for the first row
+	PrevHash string
+	// hex SHA-256 over the content of the row and prev_hash
+	Hash string
+	// the hash chain the row belongs to, rows link to the previous row of their chain
+	Chain int32
+}
+
+type Currency struct {
+	Code     string
+	Exponent int32
+	Enabled  bool
 }
 
 type Entry struct {
@@ -22,6 +227,117 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64
 	CreatedAt time.Time
+	// transfer that booked the entry, NULL for adjustments and interest
+	TransferID sql.NullInt64
+}
+
+type FeeSchedule struct {
+	ID       int64
+	Name     string
+	Currency string
+	// house account credited with the fees
+	FeeAccountID int64
+	FixedFee     int64
+	// basis points of the amount, charged over the free tier
+	PercentageBps int64
+	// basis points of the amount when the accounts have different currencies
+	CrossCurrencyBps int64
+	InstantFee       int64
+	MinFee           int64
+	// NULL for no cap
+	MaxFee                sql.NullInt64
+	FreeTransfersPerMonth int64
+	CreatedAt             time.Time
+}
+
+type InterestAccrual struct {
+	ID             int64
+	AccountID      int64
+	InterestPlanID int64
+	AccrualDate    time.Time
+	// end of day balance
+	Balance int64
+	// interest in millionths of a minor unit
+	AmountMicros int64
+	CreatedAt    time.Time
+}
+
+type InterestPlan struct {
+	ID            int64
+	Name          string
+	AnnualRateBps int64
+	// ACT/365, ACT/360, ACT/ACT or 30E/360
+	DayCount string
+	// house account paying the interest
+	InterestAccountID int64
+	CreatedAt         time.Time
+	// annual rate charged on negative balances
+	OverdraftRateBps int64
+}
+
+type InterestPosting struct {
+	ID          int64
+	AccountID   int64
+	PeriodStart time.Time
+	// exclusive
+	PeriodEnd time.Time
+	Amount    int64
+	EntryID   sql.NullInt64
+	CreatedAt time.Time
+}
+
+type OverdraftUsage struct {
+	ID         int64
+	AccountID  int64
+	TransferID int64
+	// how far the balance was below zero after the debit
+	Overdrawn      int64
+	OverdraftLimit int64
+	CreatedAt      time.Time
+}
+
+type PasswordReset struct {
+	ID       int64
+	Username string
+	// hex SHA-256 of the code, which only the email carries
+	SecretCodeHash string
+	IsUsed         bool
+	CreatedAt      time.Time
+	ExpiredAt      time.Time
+}
+
+type PaymentInstruction struct {
+	// debtor account
+	AccountID  int64
+	EndToEndID string
+	TransferID int64
+	CreatedAt  time.Time
+}
+
+type Session struct {
+	ID           uuid.UUID
+	Username     string
+	RefreshToken string
+	UserAgent    string
+	ClientIp     string
+	IsBlocked    bool
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+type Task struct {
+	ID          int64
+	Type        string
+	Payload     json.RawMessage
+	Status      TaskStatus
+	Attempts    int32
+	MaxAttempts int32
+	RunAt       time.Time
+	// a running task whose lease expired is claimed again, e.g. after a worker crashed
+	LockedUntil sql.NullTime
+	LastError   sql.NullString
+	CreatedAt   time.Time
+	CompletedAt sql.NullTime
 }
 
 type Transfer struct {
@@ -32,3 +348,44 @@ type Transfer struct {
 	Amount    int64
 	CreatedAt time.Time
 }
+
+type TransferLimit struct {
+	ID            int64
+	AccountID     sql.NullInt64
+	Owner         sql.NullString
+	MaxAmount     sql.NullInt64
+	DailyAmount   sql.NullInt64
+	MonthlyAmount sql.NullInt64
+	DailyCount    sql.NullInt64
+	CreatedAt     time.Time
+}
+
+type TransferReversal struct {
+	TransferID int64
+	// transfer moving the money back
+	ReversalID int64
+	Reason     string
+	CreatedAt  time.Time
+}
+
+type User struct {
+	Username          string
+	HashedPassword    string
+	FullName          string
+	Email             string
+	PasswordChangedAt time.Time
+	CreatedAt         time.Time
+	Role              UserRole
+	IsEmailVerified   bool
+}
+
+type VerifyEmail struct {
+	ID       int64
+	Username string
+	Email    string
+	// hex SHA-256 of the code, which only the email carries
+	SecretCodeHash string
+	IsUsed         bool
+	CreatedAt      time.Time
+	ExpiredAt      time.Time
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// ** BalanceMoney returns the account balance together with its currency
+func (account Account) BalanceMoney() money.Money {
+	return money.Money{Amount: account.Balance, Currency: account.Currency}
+}
+
+// ** OverdraftLimitMoney returns the overdraft facility of the account together with its currency
+func (account Account) OverdraftLimitMoney() money.Money {
+	return money.Money{Amount: account.OverdraftLimit, Currency: account.Currency}
+}
+
+// ** NewTransferTxParams builds the params of a transfer of a positive amount of money.
+// ** TransferTx rejects the transfer unless both accounts hold the amount's currency.
+func NewTransferTxParams(fromAccountID, toAccountID int64, amount money.Money) (TransferTxParams, error) {
+	if _, err := money.LookupCurrency(amount.Currency); err != nil {
+		return TransferTxParams{}, err
+	}
+	if !amount.IsPositive() {
+		return TransferTxParams{}, fmt.Errorf("%w: transfer amount %s must be positive", money.ErrInvalidAmount, amount)
+	}
+	return TransferTxParams{
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+		Amount:        amount.Amount,
+		Currency:      amount.Currency,
+	}, nil
+}
+
+// ** checkTransferCurrency makes sure both accounts hold the currency of the transfer, if one is given
+func checkTransferCurrency(arg TransferTxParams, fromAccount, toAccount Account) error {
+	if arg.Currency == "" {
+		return nil
+	}
+	for _, account := range []Account{fromAccount, toAccount} {
+		if account.Currency != arg.Currency {
+			return fmt.Errorf("%w: account %d holds %s, transfer is in %s", money.ErrCurrencyMismatch, account.ID, account.Currency, arg.Currency)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+)
+
+func TestNewTransferTxParams(t *testing.T) {
+	arg, err := NewTransferTxParams(1, 2, money.Money{Amount: 1234, Currency: "EUR"})
+	require.NoError(t, err)
+	require.Equal(t, TransferTxParams{FromAccountID: 1, ToAccountID: 2, Amount: 1234, Currency: "EUR"}, arg)
+
+	_, err = NewTransferTxParams(1, 2, money.Money{Amount: 0, Currency: "EUR"})
+	require.ErrorIs(t, err, money.ErrInvalidAmount)
+
+	_, err = NewTransferTxParams(1, 2, money.Money{Amount: 100, Currency: "ABC"})
+	require.ErrorIs(t, err, money.ErrUnknownCurrency)
+}
+
+func TestCheckTransferCurrency(t *testing.T) {
+	eur := Account{ID: 1, Currency: "EUR"}
+	usd := Account{ID: 2, Currency: "USD"}
+
+	require.NoError(t, checkTransferCurrency(TransferTxParams{}, eur, usd))
+	require.NoError(t, checkTransferCurrency(TransferTxParams{Currency: "EUR"}, eur, eur))
+	require.ErrorIs(t, checkTransferCurrency(TransferTxParams{Currency: "EUR"}, eur, usd), money.ErrCurrencyMismatch)
+}
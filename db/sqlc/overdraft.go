@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// ** ErrInsufficientFunds is returned when a debit would take an account below its overdraft limit
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ** Available returns how much can be debited: the balance plus the overdraft facility
+func (account Account) Available() int64 {
+	return account.Balance + account.OverdraftLimit
+}
+
+// ** checkFunds makes sure the locked account can be debited the amount
+func checkFunds(account Account, amount int64) error {
+	if amount > account.Available() {
+		return fmt.Errorf("%w: account %d has %d available, %d needed", ErrInsufficientFunds, account.ID, account.Available(), amount)
+	}
+	return nil
+}
+
+// ** recordOverdraftUsage records that a transfer left the account below zero.
+// ** It returns nil without recording anything when the account is not overdrawn.
+func recordOverdraftUsage(ctx context.Context, q *Queries, account Account, transferID int64) (*OverdraftUsage, error) {
+	if account.Balance >= 0 {
+		return nil, nil
+	}
+	usage, err := q.CreateOverdraftUsage(ctx, CreateOverdraftUsageParams{
+		AccountID:      account.ID,
+		TransferID:     transferID,
+		Overdrawn:      -account.Balance,
+		OverdraftLimit: account.OverdraftLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// ** SetOverdraftLimit grants, changes or, with a zero limit, withdraws the overdraft facility of an account.
+// ** The limit is in the currency of the account and cannot be less than the account already uses.
+func (store *SQLStore) SetOverdraftLimit(ctx context.Context, accountID int64, limit money.Money) (Account, error) {
+	if limit.IsNegative() {
+		return Account{}, fmt.Errorf("%w: overdraft limit %s is negative", money.ErrInvalidAmount, limit)
+	}
+
+	var account Account
+	err := store.execAuditedTx(ctx, "account.overdraft_limit", func(q *Queries) (auditChange, error) {
+		before, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return auditChange{}, err
+		}
+		if before.Currency != limit.Currency {
+			return auditChange{}, fmt.Errorf("%w: account %d is in %s, limit in %s", money.ErrCurrencyMismatch, before.ID, before.Currency, limit.Currency)
+		}
+		if before.Balance < -limit.Amount {
+			return auditChange{}, fmt.Errorf("%w: account %d is overdrawn by %s", ErrInsufficientFunds, before.ID, money.Money{Amount: -before.Balance, Currency: before.Currency})
+		}
+
+		account, err = q.UpdateAccountOverdraftLimit(ctx, UpdateAccountOverdraftLimitParams{
+			ID:             accountID,
+			OverdraftLimit: limit.Amount,
+		})
+		return auditChange{Entity: "account", EntityID: accountID, Before: before, After: account}, err
+	})
+	return account, err
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** createOverdraftAccount creates an account with the given overdraft limit
+func createOverdraftAccount(t *testing.T, store *SQLStore, currency string, limit int64) Account {
+	account := createRandomAccountIn(t, store.Queries, currency)
+
+	account, err := store.SetOverdraftLimit(context.Background(), account.ID, money.Money{Amount: limit, Currency: currency})
+	require.NoError(t, err)
+	require.Equal(t, limit, account.OverdraftLimit)
+	return account
+}
+
+// ** Test Set Overdraft Limit
+func TestSetOverdraftLimit(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account := createRandomAccount(t, q)
+	require.Zero(t, account.OverdraftLimit)
+	require.Equal(t, account.Balance, account.Available())
+
+	_, err := store.SetOverdraftLimit(ctx, account.ID, money.Money{Amount: -1, Currency: account.Currency})
+	require.ErrorIs(t, err, money.ErrInvalidAmount)
+
+	other := "EUR"
+	if account.Currency == other {
+		other = "USD"
+	}
+	_, err = store.SetOverdraftLimit(ctx, account.ID, money.Money{Amount: 500, Currency: other})
+	require.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	updated, err := store.SetOverdraftLimit(ctx, account.ID, money.Money{Amount: 500, Currency: account.Currency})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), updated.OverdraftLimit)
+	require.Equal(t, account.Balance+500, updated.Available())
+	require.Equal(t, account.Version+1, updated.Version)
+}
+
+// ** Test Transfer Tx into the overdraft
+func TestTransferTxOverdraft(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account1 := createOverdraftAccount(t, store, testRand.Currency(), 500)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	// ** covered by the balance: no overdraft is used
+	result, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Overdraft)
+	require.Zero(t, result.FromAccount.Balance)
+
+	// ** beyond the limit the transfer is refused before anything is booked
+	_, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        501,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// ** within the limit the account goes below zero and the usage is recorded
+	result, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        300,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-300), result.FromAccount.Balance)
+	require.NotNil(t, result.Overdraft)
+	require.Equal(t, account1.ID, result.Overdraft.AccountID)
+	require.Equal(t, result.Transfer.ID, result.Overdraft.TransferID)
+	require.Equal(t, int64(300), result.Overdraft.Overdrawn)
+	require.Equal(t, int64(500), result.Overdraft.OverdraftLimit)
+
+	usages, err := store.ListOverdraftUsages(ctx, ListOverdraftUsagesParams{
+		AccountID: account1.ID,
+		Limit:     10,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []OverdraftUsage{*result.Overdraft}, usages)
+
+	// ** the limit cannot be lowered below what is used
+	_, err = store.SetOverdraftLimit(ctx, account1.ID, money.Money{Amount: 299, Currency: account1.Currency})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// ** the schema refuses a balance below the limit even without TransferTx
+	_, err = store.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     account1.ID,
+		Amount: -201,
+	})
+	requireConstraint(t, err, ErrCheckViolation, "accounts_balance_check")
+}
+
+// ** Test Post Interest Tx charging overdraft interest
+func TestPostInterestTxOverdraft(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account := createOverdraftAccount(t, store, testRand.Currency(), 500)
+	house := createRandomAccountIn(t, q, account.Currency)
+	plan := createRandomInterestPlan(t, q, house)
+
+	_, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   house.ID,
+		Amount:        account.Balance + 100,
+	})
+	require.NoError(t, err)
+
+	periodStart := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	// ** 3 days of -0.6 minor units each
+	for day := 0; day < 3; day++ {
+		_, err := store.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:      account.ID,
+			InterestPlanID: plan.ID,
+			AccrualDate:    periodStart.AddDate(0, 0, day),
+			Balance:        -100,
+			AmountMicros:   -600000,
+		})
+		require.NoError(t, err)
+	}
+
+	houseBefore, err := store.GetAccount(ctx, house.ID)
+	require.NoError(t, err)
+
+	result, err := store.PostInterestTx(ctx, PostInterestTxParams{
+		AccountID:         account.ID,
+		InterestAccountID: house.ID,
+		PeriodStart:       periodStart,
+		PeriodEnd:         periodEnd,
+	})
+	require.NoError(t, err)
+
+	// ** -1.8 minor units accrued, rounded toward zero to -1
+	require.Equal(t, int64(-1), result.Posting.Amount)
+	require.Equal(t, int64(-1), result.Entry.Amount)
+	require.Equal(t, int64(1), result.HouseEntry.Amount)
+	require.Equal(t, int64(-101), result.Account.Balance)
+
+	updatedHouse, err := store.GetAccount(ctx, house.ID)
+	require.NoError(t, err)
+	require.Equal(t, houseBefore.Balance+1, updatedHouse.Balance)
+}
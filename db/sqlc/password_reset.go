@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ** CreatePasswordResetTxParams contains the input parameters of the password reset creation transaction
+type CreatePasswordResetTxParams struct {
+	Username string
+	// ** only the hash of SecretCode is stored, see hashSecretCode
+	SecretCode string
+	ExpiredAt  time.Time
+	// ** AfterCreate runs inside the transaction with its Queries; an error from it rolls back the reset
+	AfterCreate func(q *Queries, reset PasswordReset) error
+}
+
+// ** CreatePasswordResetTx issues a password reset code. Like CreateUserTx, use AfterCreate
+// **  to enqueue the email carrying the code in the same transaction.
+// ** The code is audited only when it is used (see ResetPasswordTx).
+func (store *SQLStore) CreatePasswordResetTx(ctx context.Context, arg CreatePasswordResetTxParams) (PasswordReset, error) {
+	var reset PasswordReset
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		reset, err = q.CreatePasswordReset(ctx, CreatePasswordResetParams{
+			Username:       arg.Username,
+			SecretCodeHash: hashSecretCode(arg.SecretCode),
+			ExpiredAt:      arg.ExpiredAt,
+		})
+		if err != nil {
+			return err
+		}
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, reset)
+		}
+		return nil
+	})
+	return reset, err
+}
+
+// ** ResetPasswordTxParams contains the input parameters of the password reset transaction
+type ResetPasswordTxParams struct {
+	ResetID        int64
+	SecretCode     string
+	HashedPassword string
+}
+
+// ** ResetPasswordTxResult is the result of the password reset transaction
+type ResetPasswordTxResult struct {
+	User          User
+	PasswordReset PasswordReset
+	// ** RevokedSessions is the number of sessions closed by the reset
+	RevokedSessions int64
+}
+
+// ** ResetPasswordTx uses a password reset code to set a new password.
+// ** Every session of the user is revoked, since whoever asked for the reset may not be its owner.
+// ** It is audited as "user.reset_password".
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.PasswordReset, err = q.UsePasswordReset(ctx, UsePasswordResetParams{
+			ID:             arg.ResetID,
+			SecretCodeHash: hashSecretCode(arg.SecretCode),
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return fmt.Errorf("%w: password reset %d", ErrInvalidSecretCode, arg.ResetID)
+		}
+		if err != nil {
+			return err
+		}
+
+		before, err := q.GetUser(ctx, result.PasswordReset.Username)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			Username:       before.Username,
+			HashedPassword: arg.HashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.RevokedSessions, err = q.BlockUserSessions(ctx, before.Username)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "user.reset_password", auditChange{
+			Entity: "user",
+			Before: userAudit(before),
+			After:  userAudit(result.User),
+		})
+	})
+	return result, err
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+// ** Test Reset Password Tx
+func TestResetPasswordTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	user := createRandomUser(t, q)
+	session := createRandomSession(t, q, user)
+
+	secretCode := testRand.String(32)
+	reset, err := store.CreatePasswordResetTx(context.Background(), CreatePasswordResetTxParams{
+		Username:   user.Username,
+		SecretCode: secretCode,
+		ExpiredAt:  time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.False(t, reset.IsUsed)
+	require.Equal(t, hashSecretCode(secretCode), reset.SecretCodeHash)
+
+	newPassword := testRand.String(8)
+	hashedPassword, err := util.HashPassword(newPassword)
+	require.NoError(t, err)
+
+	arg := ResetPasswordTxParams{
+		ResetID:        reset.ID,
+		SecretCode:     secretCode,
+		HashedPassword: hashedPassword,
+	}
+	result, err := store.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.PasswordReset.IsUsed)
+	require.Equal(t, int64(1), result.RevokedSessions)
+	require.NoError(t, util.CheckPassword(newPassword, result.User.HashedPassword))
+	require.True(t, result.User.PasswordChangedAt.After(user.PasswordChangedAt))
+
+	session, err = q.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, session.IsBlocked)
+
+	_, err = store.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInvalidSecretCode)
+}
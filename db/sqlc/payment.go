@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ** ErrDuplicatePayment is returned when a debtor account already executed a payment instruction with the same end-to-end id
+var ErrDuplicatePayment = errors.New("duplicate payment instruction")
+
+// ** recordPaymentInstruction remembers the end-to-end id of the instruction a transfer executes.
+// ** It runs in the transaction of the transfer, so a file imported twice cannot execute an instruction twice.
+func recordPaymentInstruction(ctx context.Context, q *Queries, accountID int64, endToEndID string, transferID int64) error {
+	_, err := q.CreatePaymentInstruction(ctx, CreatePaymentInstructionParams{
+		AccountID:  accountID,
+		EndToEndID: endToEndID,
+		TransferID: transferID,
+	})
+	if errors.Is(err, ErrRecordNotFound) {
+		return fmt.Errorf("%w: account %d already executed %s", ErrDuplicatePayment, accountID, endToEndID)
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test Get Account By Identifier
+func TestGetAccountByIdentifier(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account := createRandomAccount(t, q)
+	iban := "DE89" + testRand.String(18)
+
+	identifier, err := store.CreateAccountIdentifier(ctx, CreateAccountIdentifierParams{
+		Scheme:     "IBAN",
+		Identifier: iban,
+		AccountID:  account.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, identifier.AccountID)
+
+	found, err := store.GetAccountByIdentifier(ctx, GetAccountByIdentifierParams{Scheme: "IBAN", Identifier: iban})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, found.ID)
+
+	// ** the same identifier in another scheme is another identifier
+	_, err = store.GetAccountByIdentifier(ctx, GetAccountByIdentifierParams{Scheme: "OTHR", Identifier: iban})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// ** an identifier belongs to a single account
+	_, err = store.CreateAccountIdentifier(ctx, CreateAccountIdentifierParams{
+		Scheme:     "IBAN",
+		Identifier: iban,
+		AccountID:  createRandomAccount(t, q).ID,
+	})
+	require.ErrorIs(t, err, ErrUniqueViolation)
+}
+
+// ** Test Transfer Tx with an end-to-end id
+func TestTransferTxDuplicatePayment(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+	account3 := createRandomAccountIn(t, q, account1.Currency)
+	endToEndID := "E2E-" + testRand.String(8)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		EndToEndID:    endToEndID,
+	}
+	_, err := store.TransferTx(ctx, arg)
+	require.NoError(t, err)
+
+	// ** the second execution books nothing
+	_, err = store.TransferTx(ctx, arg)
+	require.ErrorIs(t, err, ErrDuplicatePayment)
+
+	updated, err := store.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, updated.Balance)
+
+	// ** end-to-end ids are unique per sender
+	_, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account3.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		EndToEndID:    endToEndID,
+	})
+	require.NoError(t, err)
+}
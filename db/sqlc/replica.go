@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ** StoreOption changes how NewStore sets up the Store
+type StoreOption func(*SQLStore)
+
+// ** ReadReplica sends the account, listing and reporting reads below to a read-only replica.
+// ** Transactions and every other query stay on the primary. A nil replica is ignored.
+func ReadReplica(replica *pgxpool.Pool) StoreOption {
+	return func(store *SQLStore) {
+		if replica != nil {
+			store.replica = New(replica)
+		}
+	}
+}
+
+type replicaContextKey int
+
+const forcePrimaryKey replicaContextKey = iota
+
+// ** ForcePrimary returns a context whose reads go to the primary even with a replica,
+// **  for a caller that must see its own writes before the replica caught up
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey, true)
+}
+
+// ** IsPrimaryForced reports whether ctx comes from ForcePrimary
+func IsPrimaryForced(ctx context.Context) bool {
+	force, _ := ctx.Value(forcePrimaryKey).(bool)
+	return force
+}
+
+// ** reader returns the queries a replica routed read runs on
+func (store *SQLStore) reader(ctx context.Context) *Queries {
+	if store.replica == nil || IsPrimaryForced(ctx) {
+		return store.Queries
+	}
+	return store.replica
+}
+
+// ** The reads below go to the replica, if there is one. The replica lags behind the primary:
+// **  transactions lock and read their rows again, but a caller that decides a write on one of
+// **  these reads, like the interest job accruing on a balance, must use ForcePrimary.
+
+func (store *SQLStore) GetAccount(ctx context.Context, id int64) (Account, error) {
+	return classify(store.reader(ctx).GetAccount(ctx, id))
+}
+
+func (store *SQLStore) GetAccountBalanceBefore(ctx context.Context, arg GetAccountBalanceBeforeParams) (int64, error) {
+	return classify(store.reader(ctx).GetAccountBalanceBefore(ctx, arg))
+}
+
+func (store *SQLStore) GetLatestBalanceSnapshot(ctx context.Context, arg GetLatestBalanceSnapshotParams) (AccountBalanceSnapshot, error) {
+	return classify(store.reader(ctx).GetLatestBalanceSnapshot(ctx, arg))
+}
+
+func (store *SQLStore) ListAccountEntriesBetween(ctx context.Context, arg ListAccountEntriesBetweenParams) ([]Entry, error) {
+	return classify(store.reader(ctx).ListAccountEntriesBetween(ctx, arg))
+}
+
+func (store *SQLStore) ListAccountTransfersBetween(ctx context.Context, arg ListAccountTransfersBetweenParams) ([]Transfer, error) {
+	return classify(store.reader(ctx).ListAccountTransfersBetween(ctx, arg))
+}
+
+func (store *SQLStore) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
+	return classify(store.reader(ctx).ListAccounts(ctx, arg))
+}
+
+func (store *SQLStore) ListAccountsByOwner(ctx context.Context, arg ListAccountsByOwnerParams) ([]Account, error) {
+	return classify(store.reader(ctx).ListAccountsByOwner(ctx, arg))
+}
+
+func (store *SQLStore) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	return classify(store.reader(ctx).ListEntries(ctx, arg))
+}
+
+func (store *SQLStore) ListOverdraftUsages(ctx context.Context, arg ListOverdraftUsagesParams) ([]OverdraftUsage, error) {
+	return classify(store.reader(ctx).ListOverdraftUsages(ctx, arg))
+}
+
+func (store *SQLStore) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
+	return classify(store.reader(ctx).ListTransfers(ctx, arg))
+}
+
+func (store *SQLStore) ListUnreconciledAccounts(ctx context.Context) ([]ListUnreconciledAccountsRow, error) {
+	return classify(store.reader(ctx).ListUnreconciledAccounts(ctx))
+}
+
+func (store *SQLStore) SumAccountEntriesBetween(ctx context.Context, arg SumAccountEntriesBetweenParams) (int64, error) {
+	return classify(store.reader(ctx).SumAccountEntriesBetween(ctx, arg))
+}
+
+func (store *SQLStore) SumAccountEntriesSince(ctx context.Context, arg SumAccountEntriesSinceParams) (int64, error) {
+	return classify(store.reader(ctx).SumAccountEntriesSince(ctx, arg))
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test which queries a read runs on
+func TestStoreReader(t *testing.T) {
+	ctx := context.Background()
+
+	replica, err := pgxpool.New(ctx, dbSource)
+	require.NoError(t, err)
+	defer replica.Close()
+
+	store := NewStore(testDB, ReadReplica(replica)).(*SQLStore)
+	require.NotNil(t, store.replica)
+	require.Same(t, store.replica, store.reader(ctx))
+	require.Same(t, store.Queries, store.reader(ForcePrimary(ctx)))
+	require.False(t, IsPrimaryForced(ctx))
+	require.True(t, IsPrimaryForced(ForcePrimary(ctx)))
+
+	// ** without a replica everything reads the primary
+	for _, store := range []*SQLStore{NewStore(testDB).(*SQLStore), NewStore(testDB, ReadReplica(nil)).(*SQLStore)} {
+		require.Nil(t, store.replica)
+		require.Same(t, store.Queries, store.reader(ctx))
+	}
+}
+
+// ** Test that reads go to the replica and transactions stay on the primary.
+// ** The replica is closed, so anything sent to it fails.
+func TestStoreReadReplica(t *testing.T) {
+	ctx := context.Background()
+
+	replica, err := pgxpool.New(ctx, dbSource)
+	require.NoError(t, err)
+	replica.Close()
+
+	store := NewStore(testDB, ReadReplica(replica))
+	account1 := createRandomAccount(t, testQueries)
+	account2 := createRandomAccountIn(t, testQueries, account1.Currency)
+
+	_, err = store.GetAccount(ctx, account1.ID)
+	require.EqualError(t, err, "closed pool")
+	_, err = store.ListEntries(ctx, ListEntriesParams{Limit: 5})
+	require.EqualError(t, err, "closed pool")
+
+	result, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	account, err := store.GetAccount(ForcePrimary(ctx), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.FromAccount.Balance, account.Balance)
+	require.Equal(t, result.FromAccount.Version, account.Version)
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ** Errors returned by ReverseTransferTx
+var (
+	ErrAlreadyReversed    = errors.New("transfer is already reversed")
+	ErrTransferIsReversal = errors.New("transfer is itself a reversal")
+)
+
+// ** ReverseTransferTxParams contains the input parameters of the reversal transaction
+type ReverseTransferTxParams struct {
+	TransferID int64  `json:"transfer_id"`
+	Reason     string `json:"reason"`
+}
+
+// ** ReverseTransferTxResult is the result of the reversal transaction
+type ReverseTransferTxResult struct {
+	Reversal    TransferReversal `json:"reversal"`
+	Transfer    Transfer         `json:"transfer"`
+	FromAccount Account          `json:"from_account"`
+	ToAccount   Account          `json:"to_account"`
+	FromEntry   Entry            `json:"from_entry"`
+	ToEntry     Entry            `json:"to_entry"`
+	// ** set when the reversal left the original recipient overdrawn
+	Overdraft *OverdraftUsage `json:"overdraft,omitempty"`
+}
+
+// ** ReverseTransferTx moves the amount of a transfer back with a new transfer in the opposite direction,
+// **  so the original one stays in the ledger. A transfer is reversed at most once and a reversal
+// **  cannot be reversed itself. Fees charged on the original transfer are not refunded.
+// ** Both accounts must still be active. It is audited as "transfer.reverse".
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransfer(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		// ** the accounts are locked before the reversal is looked up, so concurrent reversals of
+		// **  the same transfer queue up here and the second one sees the first
+		accounts, err := lockAccounts(ctx, q, original.FromAccountID, original.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := q.GetTransferReversal(ctx, original.ID)
+		switch {
+		case err == nil && existing.TransferID == original.ID:
+			return fmt.Errorf("%w: transfer %d by transfer %d", ErrAlreadyReversed, original.ID, existing.ReversalID)
+		case err == nil:
+			return fmt.Errorf("%w: transfer %d reverses transfer %d", ErrTransferIsReversal, original.ID, existing.TransferID)
+		case !errors.Is(err, ErrRecordNotFound):
+			return err
+		}
+
+		for _, account := range accounts {
+			if err = checkAccountActive(account); err != nil {
+				return err
+			}
+		}
+		if err = checkFunds(accounts[original.ToAccountID], original.Amount); err != nil {
+			return err
+		}
+
+		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        original.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  original.ToAccountID,
+			Amount:     -original.Amount,
+			TransferID: transferID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:  original.FromAccountID,
+			Amount:     original.Amount,
+			TransferID: transferID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if original.ToAccountID < original.FromAccountID {
+			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, original.ToAccountID, -original.Amount, original.FromAccountID, original.Amount)
+		} else {
+			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, original.FromAccountID, original.Amount, original.ToAccountID, -original.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Reversal, err = q.CreateTransferReversal(ctx, CreateTransferReversalParams{
+			TransferID: original.ID,
+			ReversalID: result.Transfer.ID,
+			Reason:     arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Overdraft, err = recordOverdraftUsage(ctx, q, result.FromAccount, result.Transfer.ID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "transfer.reverse", auditChange{
+			Entity:   "transfer",
+			EntityID: original.ID,
+			Before:   accounts,
+			After:    result,
+		})
+	})
+	return result, err
+}
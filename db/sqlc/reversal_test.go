@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test Reverse Transfer Tx
+func TestReverseTransferTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Reason:     "sent to the wrong account",
+	})
+	require.NoError(t, err)
+	require.Equal(t, account2.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, result.Transfer.ToAccountID)
+	require.Equal(t, int64(10), result.Transfer.Amount)
+	require.Equal(t, int64(-10), result.FromEntry.Amount)
+	require.Equal(t, int64(10), result.ToEntry.Amount)
+	require.Equal(t, account2.Balance, result.FromAccount.Balance)
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Equal(t, transfer.Transfer.ID, result.Reversal.TransferID)
+	require.Equal(t, result.Transfer.ID, result.Reversal.ReversalID)
+	require.Equal(t, "sent to the wrong account", result.Reversal.Reason)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.Transfer.ID})
+	require.ErrorIs(t, err, ErrAlreadyReversed)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: result.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferIsReversal)
+}
+
+func TestReverseTransferTxInactiveAccount(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	_, err = store.FreezeAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.Transfer.ID})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.GetTransferReversal(context.Background(), transfer.Transfer.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
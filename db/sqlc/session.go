@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ** BlockSession revokes a single session so its refresh token cannot be used any more.
+// ** It is audited as "session.revoke".
+func (store *SQLStore) BlockSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	var session Session
+	err := store.execAuditedTx(ctx, "session.revoke", func(q *Queries) (auditChange, error) {
+		var err error
+		session, err = q.BlockSession(ctx, id)
+		return auditChange{Entity: "session", After: sessionAudit(session)}, err
+	})
+	return session, err
+}
+
+// ** BlockUserSessions revokes every session of a user, e.g. after a password change
+// **  or a stolen device, and returns the number of sessions revoked.
+// ** Access tokens already issued stay valid until they expire, which is why they are short-lived.
+// ** It is audited as "session.revoke_all".
+func (store *SQLStore) BlockUserSessions(ctx context.Context, username string) (int64, error) {
+	var revoked int64
+	err := store.execAuditedTx(ctx, "session.revoke_all", func(q *Queries) (auditChange, error) {
+		var err error
+		revoked, err = q.BlockUserSessions(ctx, username)
+		return auditChange{Entity: "user", After: map[string]interface{}{"username": username, "revoked": revoked}}, err
+	})
+	return revoked, err
+}
+
+// ** sessionAudit leaves the refresh token out of the audit log
+func sessionAudit(session Session) Session {
+	session.RefreshToken = ""
+	return session
+}
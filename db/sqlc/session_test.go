@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, q *Queries, user User) Session {
+	arg := CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: testRand.String(32),
+		UserAgent:    "test",
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	session, err := q.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.RefreshToken, session.RefreshToken)
+	require.False(t, session.IsBlocked)
+
+	return session
+}
+
+// ** Test Get Session
+func TestGetSession(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	session1 := createRandomSession(t, q, createRandomUser(t, q))
+
+	session2, err := q.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.Equal(t, session1.ID, session2.ID)
+	require.Equal(t, session1.RefreshToken, session2.RefreshToken)
+	require.WithinDuration(t, session1.ExpiresAt, session2.ExpiresAt, time.Second)
+}
+
+// ** Test Revoke Sessions
+func TestBlockSessions(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	user := createRandomUser(t, q)
+	session1 := createRandomSession(t, q, user)
+	createRandomSession(t, q, user)
+	createRandomSession(t, q, user)
+
+	sessions, err := store.ListUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+
+	blocked, err := store.BlockSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, blocked.IsBlocked)
+
+	revoked, err := store.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), revoked)
+
+	sessions, err = store.ListUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	// ** revocations are audited
+	last, err := store.GetLastAuditLog(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "session.revoke_all", last.Action)
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ** statementNumberAttempts bounds the retries of CreateAccountStatement
+const statementNumberAttempts = 3
+
+// ** CreateAccountStatement numbers the statement of an account for a period. The first time a period
+// **  is issued it gets the number after the last statement of the account; issuing it again returns that number.
+// ** Two new statements of one account issued at the same moment race for the same number, the loser retries.
+func (store *SQLStore) CreateAccountStatement(ctx context.Context, arg CreateAccountStatementParams) (AccountStatement, error) {
+	for attempt := 1; ; attempt++ {
+		statement, err := classify(store.Queries.CreateAccountStatement(ctx, arg))
+		if errors.Is(err, ErrUniqueViolation) && attempt < statementNumberAttempts {
+			continue
+		}
+		return statement, err
+	}
+}
+
+// ** GetAccountActivityParams selects the account and the half-open period [FromTime, ToTime) of a statement
+type GetAccountActivityParams struct {
+	AccountID int64
+	FromTime  time.Time
+	ToTime    time.Time
+}
+
+// ** AccountActivity is what a statement of an account is built from
+type AccountActivity struct {
+	Account        Account
+	ClosingBalance int64 // ** the balance at ToTime
+	Entries        []Entry
+	Transfers      []Transfer
+}
+
+// ** GetAccountActivity reads an account, its entries and transfers within the period and its balance at
+// **  the end of it from one snapshot on the primary, so a transfer committing meanwhile is either counted
+// **  in all of them or in none, and the balances of the statement reconcile with its entries.
+func (store *SQLStore) GetAccountActivity(ctx context.Context, arg GetAccountActivityParams) (AccountActivity, error) {
+	var activity AccountActivity
+
+	err := store.execSnapshot(ctx, func(q *Queries) error {
+		var err error
+		activity.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		activity.ClosingBalance, err = q.GetAccountBalanceBefore(ctx, GetAccountBalanceBeforeParams{
+			Before:    arg.ToTime,
+			AccountID: arg.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		activity.Entries, err = q.ListAccountEntriesBetween(ctx, ListAccountEntriesBetweenParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.FromTime,
+			ToTime:    arg.ToTime,
+		})
+		if err != nil {
+			return err
+		}
+
+		activity.Transfers, err = q.ListAccountTransfersBetween(ctx, ListAccountTransfersBetweenParams{
+			AccountID: arg.AccountID,
+			FromTime:  arg.FromTime,
+			ToTime:    arg.ToTime,
+		})
+		return err
+	})
+	return activity, err
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test Create Account Statement numbering
+func TestCreateAccountStatement(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccount(t, q)
+	march := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	april := march.AddDate(0, 1, 0)
+
+	first, err := store.CreateAccountStatement(ctx, CreateAccountStatementParams{
+		AccountID:   account1.ID,
+		PeriodStart: march,
+		PeriodEnd:   april,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), first.Number)
+
+	second, err := store.CreateAccountStatement(ctx, CreateAccountStatementParams{
+		AccountID:   account1.ID,
+		PeriodStart: april,
+		PeriodEnd:   april.AddDate(0, 1, 0),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), second.Number)
+
+	// ** reissuing a period keeps its number
+	again, err := store.CreateAccountStatement(ctx, CreateAccountStatementParams{
+		AccountID:   account1.ID,
+		PeriodStart: march,
+		PeriodEnd:   april,
+	})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, again.ID)
+	require.Equal(t, first.Number, again.Number)
+
+	// ** every account counts on its own
+	other, err := store.CreateAccountStatement(ctx, CreateAccountStatementParams{
+		AccountID:   account2.ID,
+		PeriodStart: march,
+		PeriodEnd:   april,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), other.Number)
+}
+
+// ** Test Get Account Activity
+func TestGetAccountActivity(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+	result, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	activity, err := store.GetAccountActivity(ctx, GetAccountActivityParams{
+		AccountID: account1.ID,
+		FromTime:  now.Add(-time.Hour),
+		ToTime:    now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.FromAccount, activity.Account)
+	require.Equal(t, result.FromAccount.Balance, activity.ClosingBalance)
+	require.Equal(t, []Entry{result.FromEntry}, activity.Entries)
+	require.Equal(t, []Transfer{result.Transfer}, activity.Transfers)
+
+	// ** a period ending before the transfer closes with the balance from before it
+	activity, err = store.GetAccountActivity(ctx, GetAccountActivityParams{
+		AccountID: account1.ID,
+		FromTime:  now.Add(-2 * time.Hour),
+		ToTime:    now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, activity.ClosingBalance)
+	require.Empty(t, activity.Entries)
+	require.Empty(t, activity.Transfers)
+
+	_, err = store.GetAccountActivity(ctx, GetAccountActivityParams{AccountID: -1, FromTime: now, ToTime: now})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -4,25 +4,106 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/techschool/simplebank/money"
 )
 
-// ** Store provides all functions to execute DB queries and transactions
-type Store struct {
+// ** Store provides all functions to execute DB queries and transactions.
+// ** Its mutating queries are audited, see audited.go.
+type Store interface {
+	Querier
+	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
+	FreezeAccount(ctx context.Context, accountID int64) (Account, error)
+	UnfreezeAccount(ctx context.Context, accountID int64) (Account, error)
+	CloseAccount(ctx context.Context, accountID int64) (Account, error)
+	ChangeAccountStatus(ctx context.Context, accountID int64, status AccountStatus, expectedVersion int64) (Account, error)
+	UpdateAccountIfUnchanged(ctx context.Context, arg UpdateAccountIfVersionParams) (Account, error)
+	SetOverdraftLimit(ctx context.Context, accountID int64, limit money.Money) (Account, error)
+	GetBalanceAt(ctx context.Context, accountID int64, at time.Time) (int64, error)
+	GetAccountActivity(ctx context.Context, arg GetAccountActivityParams) (AccountActivity, error)
+	EnabledCurrency(ctx context.Context, code string) (Currency, error)
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	CreatePasswordResetTx(ctx context.Context, arg CreatePasswordResetTxParams) (PasswordReset, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	UpdateUserRoleTx(ctx context.Context, arg UpdateUserRoleParams) (UpdateUserRoleTxResult, error)
+	VerifyAuditLog(ctx context.Context) (int, error)
+}
+
+// ** SQLStore provides all functions to execute SQL queries and transactions
+type SQLStore struct {
 	*Queries
-	db *sql.DB
+	connPool *pgxpool.Pool
+
+	// ** set by the ReadReplica option, see replica.go
+	replica *Queries
+
+	// ** set by NewTxStore: transactions become savepoints of tx, run one at a time
+	tx   pgx.Tx
+	txMu *sync.Mutex
 }
 
 // ** New Store creates a new Store
-func NewStore(db *sql.DB) *Store {
-	return &Store{
-		db:      db,
-		Queries: New(db),
+func NewStore(connPool *pgxpool.Pool, opts ...StoreOption) Store {
+	store := &SQLStore{
+		connPool: connPool,
+		Queries:  New(connPool),
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// ** NewTxStore creates a Store that runs everything inside tx, its own transactions as savepoints.
+// ** Nothing it writes is visible to other connections before tx commits, so tests can roll it all back.
+// ** A failed statement outside of a store transaction aborts tx, like it would any transaction.
+func NewTxStore(tx pgx.Tx) Store {
+	return &SQLStore{
+		Queries: New(tx),
+		tx:      tx,
+		txMu:    &sync.Mutex{},
+	}
+}
+
+// ** execSavepoint runs fn in a savepoint of the transaction of a Store created by NewTxStore.
+// ** Savepoints of one transaction cannot interleave, so concurrent calls are serialized.
+func (store *SQLStore) execSavepoint(ctx context.Context, fn func(*Queries) error) error {
+	store.txMu.Lock()
+	defer store.txMu.Unlock()
+
+	// ** Begin on a pgx.Tx creates a savepoint, its Rollback rolls back to it and its Commit releases it
+	savepoint, err := store.tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(New(savepoint))
+
+	if err != nil {
+		if rollbackError := savepoint.Rollback(ctx); rollbackError != nil {
+			return fmt.Errorf("tx error : %w, rollback error : %v", err, rollbackError)
+		}
+		return err
 	}
+	return savepoint.Commit(ctx)
 }
 
-// ** We will add a function to the Store to execute a generic database transaction
-func (store *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+// ** We will add a function to the Store to execute a generic database transaction.
+// ** Its error is classified, so constraint violations surface as a *ConstraintError.
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	if store.tx != nil {
+		return ClassifyError(store.execSavepoint(ctx, fn))
+	}
+
+	tx, err := store.connPool.Begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -30,12 +111,12 @@ func (store *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
 	err = fn(q)
 
 	if err != nil {
-		if rollbackError := tx.Rollback(); rollbackError != nil {
-			return fmt.Errorf("tx error : %v, rollback error : %v", err, rollbackError)
+		if rollbackError := tx.Rollback(ctx); rollbackError != nil {
+			return fmt.Errorf("tx error : %w, rollback error : %v", ClassifyError(err), rollbackError)
 		}
-		return err
+		return ClassifyError(err)
 	}
-	return tx.Commit()
+	return ClassifyError(tx.Commit(ctx))
 }
 
 // ** TransferTx performs a money transfer from one account to another.
@@ -49,6 +130,13 @@ type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// ** optional: when set, both accounts must hold this currency (see NewTransferTxParams)
+	Currency string `json:"currency"`
+	// ** optional: charge the sender a fee according to this schedule (0 means no fee)
+	FeeScheduleID int64 `json:"fee_schedule_id"`
+	Instant       bool  `json:"instant"`
+	// ** optional: end-to-end id of the payment instruction the transfer executes, unique per sender (see payment.go)
+	EndToEndID string `json:"end_to_end_id,omitempty"`
 }
 
 // ** TransferTxResult is the result of the transfer transaction
@@ -58,20 +146,49 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	Fee         int64    `json:"fee"`
+	FeeEntries  []Entry  `json:"fee_entries"`
+	// ** set when the transfer left the sender overdrawn
+	Overdraft *OverdraftUsage `json:"overdraft,omitempty"`
 }
 
-// ** TXKEY
-var txKey = struct{}{}
-
-func (store *Store) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
-
-	txName := ctx.Value(txKey)
-
+func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
-		fmt.Println(txName, "create transfer")
+
+		accountIDs := []int64{arg.FromAccountID, arg.ToAccountID}
+		var schedule FeeSchedule
+		if arg.FeeScheduleID != 0 {
+			schedule, err = q.GetFeeSchedule(ctx, arg.FeeScheduleID)
+			if err != nil {
+				return err
+			}
+			accountIDs = append(accountIDs, schedule.FeeAccountID)
+		}
+
+		// ** lock every account the transfer touches in id order, then make sure they can all move money
+		accounts, err := lockAccounts(ctx, q, accountIDs...)
+		if err != nil {
+			return err
+		}
+		for _, id := range accountIDs {
+			if err = checkAccountActive(accounts[id]); err != nil {
+				return err
+			}
+		}
+		fromAccount := accounts[arg.FromAccountID]
+		if err = checkTransferCurrency(arg, fromAccount, accounts[arg.ToAccountID]); err != nil {
+			return err
+		}
+		if err = checkTransferLimits(ctx, q, fromAccount, arg.Amount); err != nil {
+			return err
+		}
+		if err = checkFunds(fromAccount, arg.Amount); err != nil {
+			return err
+		}
+
 		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
 			FromAccountID: arg.FromAccountID,
 			ToAccountID:   arg.ToAccountID,
@@ -81,42 +198,34 @@ func (store *Store) TransferTx(ctx context.Context, arg TransferTxParams) (Trans
 			return err
 		}
 
-		fmt.Println(txName, "create entry 1")
+		if arg.EndToEndID != "" {
+			if err = recordPaymentInstruction(ctx, q, arg.FromAccountID, arg.EndToEndID, result.Transfer.ID); err != nil {
+				return err
+			}
+		}
+
+		// ** every entry the transfer books, the fee included, links back to it
+		transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
 		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
+			AccountID:  arg.FromAccountID,
+			Amount:     -arg.Amount,
+			TransferID: transferID,
 		})
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(txName, "create entry 2")
 		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
+			AccountID:  arg.ToAccountID,
+			Amount:     arg.Amount,
+			TransferID: transferID,
 		})
 		if err != nil {
 			return err
 		}
-		// TODO : update account balance
-		// ** get accounts -> update its balance
-
-		// ** money out of the FromAccount
-
-		// fmt.Println(txName,"get account for update")
-		// account1, err := q.GetAccountForUpdate(ctx, arg.FromAccountID)
-		// if err != nil {
-		// 	return err
-		// }
-
-		// fmt.Println(txName,"update account")
-		// result.FromAccount, err = q.UpdateAccount(ctx, UpdateAccountParams{
-		// 	ID:      arg.FromAccountID,
-		// 	Balance: account1.Balance - arg.Amount,
-		// })
-		// if err != nil {
-		// 	return err
-		// }
+
+		// ** update both balances in account id order
 		if arg.FromAccountID < arg.ToAccountID {
 			result.FromAccount,result.ToAccount,err = addMoney(ctx,q,arg.FromAccountID,- arg.Amount,arg.ToAccountID,arg.Amount)
 		} else {
@@ -124,24 +233,64 @@ func (store *Store) TransferTx(ctx context.Context, arg TransferTxParams) (Trans
 			
 
 		}
+		if err != nil {
+			return err
+		}
 
-		// ** money into to ToAccount
-		// fmt.Println(txName,"get account for update 2")
-		// account2, err := q.GetAccountForUpdate(ctx, arg.ToAccountID)
-		// if err != nil {
-		// 	return err
-		// }
+		// ** the fee moves from the sender to the house fee account as two more entries
+		if arg.FeeScheduleID != 0 {
+			result.Fee, err = computeFee(ctx, q, schedule, fromAccount, accounts[arg.ToAccountID], accounts[schedule.FeeAccountID], arg)
+			if err != nil {
+				return err
+			}
+		}
+		if result.Fee > 0 {
+			if err = checkFunds(result.FromAccount, result.Fee); err != nil {
+				return err
+			}
 
-		// fmt.Println(txName,"update account 2")
-		// result.ToAccount, err = q.UpdateAccount(ctx, UpdateAccountParams{
-		// 	ID:      arg.ToAccountID,
-		// 	Balance: account2.Balance + arg.Amount,
-		// })
-		// if err != nil {
-		// 	return err
-		// }
+			fromFeeEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+				AccountID:  arg.FromAccountID,
+				Amount:     -result.Fee,
+				TransferID: transferID,
+			})
+			if err != nil {
+				return err
+			}
 
-		return nil
+			toFeeEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+				AccountID:  schedule.FeeAccountID,
+				Amount:     result.Fee,
+				TransferID: transferID,
+			})
+			if err != nil {
+				return err
+			}
+			result.FeeEntries = []Entry{fromFeeEntry, toFeeEntry}
+
+			result.FromAccount, _, err = addMoney(ctx, q, arg.FromAccountID, -result.Fee, schedule.FeeAccountID, result.Fee)
+			if err != nil {
+				return err
+			}
+			if arg.ToAccountID == schedule.FeeAccountID {
+				result.ToAccount, err = q.GetAccount(ctx, arg.ToAccountID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		result.Overdraft, err = recordOverdraftUsage(ctx, q, result.FromAccount, result.Transfer.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, "transfer.create", auditChange{
+			Entity:   "transfer",
+			EntityID: result.Transfer.ID,
+			Before:   accounts,
+			After:    result,
+		})
 
 	})
 	return result, err
@@ -169,3 +318,23 @@ func addMoney(
 	})
 	return
 }
+
+// ** lockAccounts locks accounts in ascending id order for the rest of the transaction.
+// ** Every transaction takes its locks in the same order, so they cannot deadlock.
+func lockAccounts(ctx context.Context, q *Queries, accountIDs ...int64) (map[int64]Account, error) {
+	ids := append([]int64(nil), accountIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	accounts := make(map[int64]Account, len(ids))
+	for _, id := range ids {
+		if _, locked := accounts[id]; locked {
+			continue
+		}
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = account
+	}
+	return accounts, nil
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -11,8 +13,8 @@ import (
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := createRandomAccount(t, testQueries)
+	account2 := createRandomAccountIn(t, testQueries, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	// ** run n concurrent transfer transactions
@@ -22,11 +24,8 @@ func TestTransferTx(t *testing.T) {
 	results := make(chan TransferTxResult)
 
 	for i := 0; i < n; i++ {
-		txName := fmt.Sprintf("tx %d", i+1)
-
 		go func() {
-			ctx := context.WithValue(context.Background(), txKey, txName)
-			result, err := store.TransferTx(ctx, TransferTxParams{
+			result, err := store.TransferTx(context.Background(), TransferTxParams{
 				FromAccountID: account1.ID,
 				ToAccountID:   account2.ID,
 				Amount:        amount,
@@ -66,6 +65,7 @@ func TestTransferTx(t *testing.T) {
 		require.Equal(t, -amount, int64(fromEntry.Amount))
 		require.NotZero(t, fromEntry.ID)
 		require.NotZero(t, fromEntry.CreatedAt)
+		require.Equal(t, sql.NullInt64{Int64: transfer.ID, Valid: true}, fromEntry.TransferID)
 
 		_, err = store.GetEntry(context.Background(), fromEntry.ID)
 		require.NoError(t, err)
@@ -77,6 +77,7 @@ func TestTransferTx(t *testing.T) {
 		require.Equal(t, amount, toEntry.Amount)
 		require.NotZero(t, toEntry.ID)
 		require.NotZero(t, toEntry.CreatedAt)
+		require.Equal(t, sql.NullInt64{Int64: transfer.ID, Valid: true}, toEntry.TransferID)
 
 		_, err = store.GetEntry(context.Background(), toEntry.ID)
 		require.NoError(t, err)
@@ -125,8 +126,8 @@ func TestTransferTx(t *testing.T) {
 func TestTransferTwoTransactionDeadlockTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := createRandomAccount(t, testQueries)
+	account2 := createRandomAccountIn(t, testQueries, account1.Currency)
 
 	// ** run n concurrent transfer transactions
 	n := 10
@@ -168,3 +169,122 @@ func TestTransferTwoTransactionDeadlockTx(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestAccountStatusTransitions(t *testing.T) {
+	require.True(t, AccountStatusActive.CanTransitionTo(AccountStatusFrozen))
+	require.True(t, AccountStatusActive.CanTransitionTo(AccountStatusClosed))
+	require.True(t, AccountStatusFrozen.CanTransitionTo(AccountStatusActive))
+	require.False(t, AccountStatusFrozen.CanTransitionTo(AccountStatusClosed))
+	require.False(t, AccountStatusClosed.CanTransitionTo(AccountStatusActive))
+	require.False(t, AccountStatusClosed.CanTransitionTo(AccountStatusFrozen))
+}
+
+func TestFreezeAccount(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	frozen, err := store.FreezeAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, frozen.Status)
+
+	// ** a frozen account can neither send nor receive money
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	var statusErr *AccountNotActiveError
+	require.True(t, errors.As(err, &statusErr))
+	require.Equal(t, account1.ID, statusErr.AccountID)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	// ** nothing has been booked
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	_, err = store.FreezeAccount(context.Background(), account1.ID)
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	active, err := store.UnfreezeAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusActive, active.Status)
+}
+
+func TestCloseAccount(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	// ** the balance has to be moved out first
+	if account1.Balance != 0 {
+		_, err := store.CloseAccount(context.Background(), account1.ID)
+		require.ErrorIs(t, err, ErrNonZeroBalance)
+
+		_, err = store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        account1.Balance,
+		})
+		require.NoError(t, err)
+	}
+
+	closed, err := store.CloseAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, closed.Status)
+	require.Zero(t, closed.Balance)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	_, err = store.UnfreezeAccount(context.Background(), account1.ID)
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+}
+
+func TestNewTxStore(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	store := NewTxStore(tx)
+	account1, err := store.CreateAccount(ctx, CreateAccountParams{
+		Owner:    testRand.Owner(),
+		Balance:  100,
+		Currency: testRand.Currency(),
+	})
+	require.NoError(t, err)
+
+	// ** a failed store transaction only rolls back its savepoint
+	_, err = store.TransferTx(ctx, TransferTxParams{FromAccountID: account1.ID, ToAccountID: -1, Amount: 10})
+	require.Error(t, err)
+
+	account2, err := store.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1, account2)
+
+	// ** nothing is visible outside of tx
+	_, err = testQueries.GetAccount(ctx, account1.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	require.NoError(t, tx.Rollback(ctx))
+}
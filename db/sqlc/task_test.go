@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTask(t *testing.T, runAt time.Time) Task {
+	arg := CreateTaskParams{
+		Type:        "task:" + testRand.String(8),
+		Payload:     json.RawMessage(`{"id":1}`),
+		MaxAttempts: 3,
+		RunAt:       runAt,
+	}
+
+	task, err := testQueries.CreateTask(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, task.ID)
+	require.Equal(t, arg.Type, task.Type)
+	require.JSONEq(t, string(arg.Payload), string(task.Payload))
+	require.Equal(t, TaskStatusPending, task.Status)
+	require.Zero(t, task.Attempts)
+	require.Equal(t, arg.MaxAttempts, task.MaxAttempts)
+	require.WithinDuration(t, arg.RunAt, task.RunAt, time.Second)
+
+	return task
+}
+
+// ** claimUntil claims due tasks until it gets the wanted one, completing the others,
+// **  since tasks left by other tests may be due as well
+func claimUntil(t *testing.T, q *Queries, id int64) Task {
+	for {
+		task, err := q.ClaimTask(context.Background(), testLease)
+		require.NoError(t, err)
+		if task.ID == id {
+			return task
+		}
+		completeClaimedTask(t, q, task)
+	}
+}
+
+// ** testLease is the lease of tasks claimed by the tests, in seconds
+const testLease = 60
+
+// ** completeClaimedTask completes a task claimed by the tests
+func completeClaimedTask(t *testing.T, q *Queries, task Task) {
+	updated, err := q.CompleteTask(context.Background(), CompleteTaskParams{ID: task.ID, LockedUntil: task.LockedUntil})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+}
+
+// ** Test Claim Task
+func TestClaimTask(t *testing.T) {
+	task1 := createRandomTask(t, time.Now().Add(-time.Second))
+
+	task2 := claimUntil(t, testQueries, task1.ID)
+	require.Equal(t, TaskStatusRunning, task2.Status)
+	require.Equal(t, int32(1), task2.Attempts)
+	require.True(t, task2.LockedUntil.Valid)
+	require.WithinDuration(t, time.Now().Add(testLease*time.Second), task2.LockedUntil.Time, 5*time.Second)
+
+	runAt := time.Now().Add(-time.Second)
+	updated, err := testQueries.RetryTask(context.Background(), RetryTaskParams{
+		ID:          task1.ID,
+		RunAt:       runAt,
+		LastError:   sql.NullString{String: "failed", Valid: true},
+		LockedUntil: task2.LockedUntil,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+
+	task3 := claimUntil(t, testQueries, task1.ID)
+	require.Equal(t, int32(2), task3.Attempts)
+	require.Equal(t, "failed", task3.LastError.String)
+
+	// ** the run of the first claim has lost its lease and cannot record an outcome any more
+	updated, err = testQueries.CompleteTask(context.Background(), CompleteTaskParams{ID: task1.ID, LockedUntil: task2.LockedUntil})
+	require.NoError(t, err)
+	require.Zero(t, updated)
+
+	completeClaimedTask(t, testQueries, task3)
+	task4, err := testQueries.GetTask(context.Background(), task1.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusDone, task4.Status)
+	require.True(t, task4.CompletedAt.Valid)
+	require.False(t, task4.LockedUntil.Valid)
+	// ** a payload may carry a secret code, it is not kept once the task is done
+	require.JSONEq(t, `{}`, string(task4.Payload))
+}
+
+// ** Test Kill Task
+func TestKillTask(t *testing.T) {
+	task1 := createRandomTask(t, time.Now().Add(-time.Second))
+	task2 := claimUntil(t, testQueries, task1.ID)
+
+	updated, err := testQueries.KillTask(context.Background(), KillTaskParams{
+		ID:          task1.ID,
+		LastError:   sql.NullString{String: "failed", Valid: true},
+		LockedUntil: task2.LockedUntil,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+
+	task3, err := testQueries.GetTask(context.Background(), task1.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusDead, task3.Status)
+	require.Equal(t, "failed", task3.LastError.String)
+	require.False(t, task3.LockedUntil.Valid)
+	// ** nor once it is given up on
+	require.JSONEq(t, `{}`, string(task3.Payload))
+}
+
+// ** Test Claim Task Skips Locked
+func TestClaimTaskSkipsLocked(t *testing.T) {
+	task1 := createRandomTask(t, time.Now().Add(-time.Second))
+
+	// ** while a transaction holds the row of a claimed task, other workers skip it
+	tx, err := testDB.Begin(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback(context.Background())
+	claimUntil(t, New(tx), task1.ID)
+
+	for {
+		task, err := testQueries.ClaimTask(context.Background(), testLease)
+		if err == ErrRecordNotFound {
+			break
+		}
+		require.NoError(t, err)
+		require.NotEqual(t, task1.ID, task.ID)
+		completeClaimedTask(t, testQueries, task)
+	}
+}
+
+// ** Test Claim Task Scheduled
+func TestClaimTaskScheduled(t *testing.T) {
+	task1 := createRandomTask(t, time.Now().Add(time.Hour))
+
+	for {
+		task, err := testQueries.ClaimTask(context.Background(), testLease)
+		if err == ErrRecordNotFound {
+			break
+		}
+		require.NoError(t, err)
+		require.NotEqual(t, task1.ID, task.ID)
+		completeClaimedTask(t, testQueries, task)
+	}
+
+	// ** only a running task can be killed
+	updated, err := testQueries.KillTask(context.Background(), KillTaskParams{
+		ID:        task1.ID,
+		LastError: sql.NullString{String: "cancelled", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, updated)
+
+	task2, err := testQueries.GetTask(context.Background(), task1.ID)
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusPending, task2.Status)
+}
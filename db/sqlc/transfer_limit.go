@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ** ErrLimitExceeded is returned (wrapped in a *LimitExceededError) when a transfer breaks a limit
+var ErrLimitExceeded = errors.New("transfer limit exceeded")
+
+// ** Which limit was hit
+const (
+	LimitSingleAmount  = "single_amount"
+	LimitDailyAmount   = "daily_amount"
+	LimitMonthlyAmount = "monthly_amount"
+	LimitDailyCount    = "daily_count"
+)
+
+// ** Whose limit was hit
+const (
+	LimitScopeAccount = "account"
+	LimitScopeOwner   = "owner"
+)
+
+// ** LimitExceededError details the limit a transfer would break and the headroom left under it
+type LimitExceededError struct {
+	Scope     string
+	Limit     string
+	Max       int64
+	Used      int64
+	Remaining int64
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s %s limit of %d exceeded: %d used, %d remaining", e.Scope, e.Limit, e.Max, e.Used, e.Remaining)
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// ** limitUsage is what has already been transferred in the current day and month
+type limitUsage struct {
+	dailyCount   int64
+	dailyTotal   int64
+	monthlyTotal int64
+}
+
+// ** check returns a *LimitExceededError for the first limit the transfer of amount would break
+func (limit TransferLimit) check(scope string, usage limitUsage, amount int64) error {
+	exceeded := func(name string, max sql.NullInt64, used, requested int64) error {
+		if !max.Valid || used+requested <= max.Int64 {
+			return nil
+		}
+		remaining := max.Int64 - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		return &LimitExceededError{Scope: scope, Limit: name, Max: max.Int64, Used: used, Remaining: remaining}
+	}
+
+	if err := exceeded(LimitSingleAmount, limit.MaxAmount, 0, amount); err != nil {
+		return err
+	}
+	if err := exceeded(LimitDailyCount, limit.DailyCount, usage.dailyCount, 1); err != nil {
+		return err
+	}
+	if err := exceeded(LimitDailyAmount, limit.DailyAmount, usage.dailyTotal, amount); err != nil {
+		return err
+	}
+	return exceeded(LimitMonthlyAmount, limit.MonthlyAmount, usage.monthlyTotal, amount)
+}
+
+// ** checkTransferLimits evaluates the limits of the sending account and of its owner.
+// ** An owner's amount limits hold for each currency on its own: they are compared with the
+// **  transfers from the owner's accounts in the currency of the sending account, while the
+// **  daily count covers the owner's transfers in every currency.
+// ** The account row is already locked by TransferTx; owner limits are serialized with an advisory lock,
+// **  so concurrent transfers from different accounts of the same owner cannot both pass.
+func checkTransferLimits(ctx context.Context, q *Queries, account Account, amount int64) error {
+	now := time.Now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	accountLimit, err := q.GetAccountTransferLimit(ctx, account.ID)
+	switch {
+	case errors.Is(err, ErrRecordNotFound):
+	case err != nil:
+		return err
+	default:
+		usage, err := accountUsage(ctx, q, account.ID, dayStart, monthStart)
+		if err != nil {
+			return err
+		}
+		if err := accountLimit.check(LimitScopeAccount, usage, amount); err != nil {
+			return err
+		}
+	}
+
+	ownerLimit, err := q.GetOwnerTransferLimit(ctx, account.Owner)
+	switch {
+	case errors.Is(err, ErrRecordNotFound):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if err := q.LockOwnerTransfers(ctx, account.Owner); err != nil {
+		return err
+	}
+	usage, err := ownerUsage(ctx, q, account.Owner, account.Currency, dayStart, monthStart)
+	if err != nil {
+		return err
+	}
+	return ownerLimit.check(LimitScopeOwner, usage, amount)
+}
+
+func accountUsage(ctx context.Context, q *Queries, accountID int64, dayStart, monthStart time.Time) (limitUsage, error) {
+	daily, err := q.GetAccountTransferTotals(ctx, GetAccountTransferTotalsParams{AccountID: accountID, Since: dayStart})
+	if err != nil {
+		return limitUsage{}, err
+	}
+	monthly, err := q.GetAccountTransferTotals(ctx, GetAccountTransferTotalsParams{AccountID: accountID, Since: monthStart})
+	if err != nil {
+		return limitUsage{}, err
+	}
+	return limitUsage{dailyCount: daily.Count, dailyTotal: daily.Total, monthlyTotal: monthly.Total}, nil
+}
+
+func ownerUsage(ctx context.Context, q *Queries, owner, currency string, dayStart, monthStart time.Time) (limitUsage, error) {
+	daily, err := q.GetOwnerTransferTotals(ctx, GetOwnerTransferTotalsParams{
+		Currency: currency,
+		Owner:    owner,
+		Since:    dayStart,
+	})
+	if err != nil {
+		return limitUsage{}, err
+	}
+	monthly, err := q.GetOwnerTransferTotals(ctx, GetOwnerTransferTotalsParams{
+		Currency: currency,
+		Owner:    owner,
+		Since:    monthStart,
+	})
+	if err != nil {
+		return limitUsage{}, err
+	}
+	return limitUsage{dailyCount: daily.Count, dailyTotal: daily.Total, monthlyTotal: monthly.Total}, nil
+}
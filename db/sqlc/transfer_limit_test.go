@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func limitOf(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: true}
+}
+
+// ** Test Transfer Limit Check
+func TestTransferLimitCheck(t *testing.T) {
+	limit := TransferLimit{
+		MaxAmount:     limitOf(100),
+		DailyAmount:   limitOf(250),
+		MonthlyAmount: limitOf(1000),
+		DailyCount:    limitOf(3),
+	}
+
+	testCases := []struct {
+		name      string
+		usage     limitUsage
+		amount    int64
+		limit     string
+		remaining int64
+	}{
+		{"within limits", limitUsage{dailyCount: 1, dailyTotal: 50, monthlyTotal: 50}, 100, "", 0},
+		{"single amount", limitUsage{}, 101, LimitSingleAmount, 100},
+		{"daily count", limitUsage{dailyCount: 3, dailyTotal: 30, monthlyTotal: 30}, 10, LimitDailyCount, 0},
+		{"daily amount", limitUsage{dailyCount: 2, dailyTotal: 200, monthlyTotal: 200}, 60, LimitDailyAmount, 50},
+		{"monthly amount", limitUsage{dailyCount: 0, dailyTotal: 0, monthlyTotal: 990}, 20, LimitMonthlyAmount, 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := limit.check(LimitScopeAccount, tc.usage, tc.amount)
+			if tc.limit == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrLimitExceeded)
+			var limitErr *LimitExceededError
+			require.True(t, errors.As(err, &limitErr))
+			require.Equal(t, LimitScopeAccount, limitErr.Scope)
+			require.Equal(t, tc.limit, limitErr.Limit)
+			require.Equal(t, tc.remaining, limitErr.Remaining)
+		})
+	}
+
+	// ** a limit without values does not restrict anything
+	require.NoError(t, TransferLimit{}.check(LimitScopeOwner, limitUsage{dailyCount: 100, dailyTotal: 1e9}, 1e9))
+}
+
+// ** Test Set Account Transfer Limit
+func TestSetAccountTransferLimit(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	account := createRandomAccount(t, q)
+
+	limit1, err := q.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID: limitOf(account.ID),
+		MaxAmount: limitOf(100),
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, limit1.AccountID.Int64)
+	require.False(t, limit1.Owner.Valid)
+	require.Equal(t, int64(100), limit1.MaxAmount.Int64)
+	require.False(t, limit1.DailyAmount.Valid)
+
+	// ** setting the limit again replaces it
+	limit2, err := q.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID:  limitOf(account.ID),
+		DailyCount: limitOf(5),
+	})
+	require.NoError(t, err)
+	require.Equal(t, limit1.ID, limit2.ID)
+	require.False(t, limit2.MaxAmount.Valid)
+	require.Equal(t, int64(5), limit2.DailyCount.Int64)
+
+	limit3, err := q.GetAccountTransferLimit(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, limit2, limit3)
+
+	require.NoError(t, q.DeleteTransferLimit(context.Background(), limit1.ID))
+	_, err = q.GetAccountTransferLimit(context.Background(), account.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+// ** Test Transfer Tx Account Limits
+func TestTransferTxAccountLimits(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	_, err := store.SetAccountTransferLimit(context.Background(), SetAccountTransferLimitParams{
+		AccountID:   limitOf(account1.ID),
+		MaxAmount:   limitOf(50),
+		DailyAmount: limitOf(60),
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        51,
+	})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitSingleAmount, limitErr.Limit)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        40,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+	})
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(40), limitErr.Used)
+	require.Equal(t, int64(20), limitErr.Remaining)
+
+	// ** incoming transfers are not limited
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+}
+
+// ** Test Transfer Tx Owner Limits
+func TestTransferTxOwnerLimits(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	_, err := store.SetOwnerTransferLimit(context.Background(), SetOwnerTransferLimitParams{
+		Owner:      sql.NullString{String: account1.Owner, Valid: true},
+		DailyCount: limitOf(1),
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitScopeOwner, limitErr.Scope)
+	require.Equal(t, LimitDailyCount, limitErr.Limit)
+}
+
+// ** Test Transfer Tx Owner Limits across currencies
+func TestTransferTxOwnerLimitsPerCurrency(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+	ctx := context.Background()
+
+	eur := createRandomAccountIn(t, q, "EUR")
+	usd, err := q.CreateAccount(ctx, CreateAccountParams{
+		Owner:    eur.Owner,
+		Balance:  1000,
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+	eurPayee := createRandomAccountIn(t, q, "EUR")
+	usdPayee := createRandomAccountIn(t, q, "USD")
+
+	_, err = store.SetOwnerTransferLimit(ctx, SetOwnerTransferLimitParams{
+		Owner:       sql.NullString{String: eur.Owner, Valid: true},
+		DailyAmount: limitOf(100),
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: eur.ID,
+		ToAccountID:   eurPayee.ID,
+		Amount:        80,
+	})
+	require.NoError(t, err)
+
+	// ** dollars are not added to euros
+	_, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: usd.ID,
+		ToAccountID:   usdPayee.ID,
+		Amount:        80,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: eur.ID,
+		ToAccountID:   eurPayee.ID,
+		Amount:        30,
+	})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitScopeOwner, limitErr.Scope)
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(80), limitErr.Used)
+	require.Equal(t, int64(20), limitErr.Remaining)
+}
@@ -2,28 +2,26 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
-	"github.com/techschool/simplebank/util"
 )
 
 // ** for testing we should create an transfer first
-func createRandomTransfer(t *testing.T) Transfer {
+func createRandomTransfer(t *testing.T, q *Queries) Transfer {
 
 	// ** Create Account
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
 
 	// ** Create Account Get Tests
-	account1GetTest, error1 := testQueries.GetAccount(context.Background(),account1.ID)
+	account1GetTest, error1 := q.GetAccount(context.Background(),account1.ID)
 	require.NoError(t, error1)
 	require.NotEmpty(t, account1GetTest)
 	require.Equal(t, account1GetTest.ID, account1.ID)
 
-	account2GetTest, error2 := testQueries.GetAccount(context.Background(),account2.ID)
+	account2GetTest, error2 := q.GetAccount(context.Background(),account2.ID)
 	require.NoError(t, error2)
 	require.NotEmpty(t, account2GetTest)
 	require.Equal(t, account2GetTest.ID, account2.ID)
@@ -32,10 +30,10 @@ func createRandomTransfer(t *testing.T) Transfer {
 	arg := CreateTransferParams{
 		FromAccountID: account1.ID,
 		ToAccountID: account2.ID,
-		Amount: util.RandomMoney(),
+		Amount: testRand.AmountWithin(account1.Balance),
 	}
 
-	transfer, transferError := testQueries.CreateTransfer(context.Background(),arg)
+	transfer, transferError := q.CreateTransfer(context.Background(),arg)
 	require.NoError(t,transferError)
 	require.NotEmpty(t,transfer)
 	require.Equal(t,arg.ToAccountID,transfer.ToAccountID)
@@ -48,13 +46,19 @@ func createRandomTransfer(t *testing.T) Transfer {
 
 // ** Test Create Transfer
 func TestCreateTransfer(t *testing.T) {
-	createRandomTransfer(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	createRandomTransfer(t, q)
 }
 
 // ** Test Get Transfer
 func TestGetTransfer(t *testing.T){
-	transfer1 := createRandomTransfer(t)
-	transfer2, transferError := testQueries.GetTransfer(context.Background(),transfer1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	transfer1 := createRandomTransfer(t, q)
+	transfer2, transferError := q.GetTransfer(context.Background(),transfer1.ID)
 
 	require.NoError(t,transferError)
 	require.NotEmpty(t,transfer2)
@@ -69,13 +73,16 @@ func TestGetTransfer(t *testing.T){
 
 // ** Test Update Transfer
 func TestUpdateTransfer(t *testing.T) {
-	transfer1 := createRandomTransfer(t)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	transfer1 := createRandomTransfer(t, q)
 	arg := UpdateTransferParams{
 		ID: transfer1.ID,
-		Amount: util.RandomAmount(),
+		Amount: testRand.AmountWithin(1000),
 	}
 
-	transfer2, transferError := testQueries.UpdateTransfer(context.Background(),arg)
+	transfer2, transferError := q.UpdateTransfer(context.Background(),arg)
 	require.NoError(t,transferError)
 	require.NotEmpty(t,transfer2)
 
@@ -89,14 +96,35 @@ func TestUpdateTransfer(t *testing.T) {
 
 // ** Test Delete Transfer
 func TestDeleteTransfer(t *testing.T) {
-	transfer1 := createRandomTransfer(t)
-	transferError := testQueries.DeleteTransfer(context.Background(),transfer1.ID)
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	transfer1 := createRandomTransfer(t, q)
+	transferError := q.DeleteTransfer(context.Background(),transfer1.ID)
 	require.NoError(t,transferError)
 
 	// ** is transfer really deleted?
-	transfer2, transferError2 := testQueries.GetTransfer(context.Background(),transfer1.ID)
+	transfer2, transferError2 := q.GetTransfer(context.Background(),transfer1.ID)
 	require.Error(t,transferError2)
-	require.EqualError(t,transferError2,sql.ErrNoRows.Error())
+	require.EqualError(t,transferError2,ErrRecordNotFound.Error())
 	require.Empty(t,transfer2)
 }
 
+// ** Test List Account Transfers Between
+func TestListAccountTransfersBetween(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	transfer1 := createRandomTransfer(t, q)
+
+	arg := ListAccountTransfersBetweenParams{
+		AccountID: transfer1.ToAccountID,
+		FromTime:  transfer1.CreatedAt.Add(-time.Minute),
+		ToTime:    transfer1.CreatedAt.Add(time.Minute),
+	}
+
+	transfers, transferError := q.ListAccountTransfersBetween(context.Background(), arg)
+	require.NoError(t, transferError)
+	require.Len(t, transfers, 1)
+	require.Equal(t, transfer1.ID, transfers[0].ID)
+}
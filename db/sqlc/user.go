@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+)
+
+// ** UpdateUserRoleTxResult is the result of the role change transaction
+type UpdateUserRoleTxResult struct {
+	User User
+	// ** RevokedSessions is the number of sessions closed by the change
+	RevokedSessions int64
+}
+
+// ** UpdateUserRoleTx grants a role to a user and revokes every session of the user in the same transaction,
+// **  so no refresh token issued before the change can be renewed once the role is committed.
+// ** It is audited as "user.role".
+func (store *SQLStore) UpdateUserRoleTx(ctx context.Context, arg UpdateUserRoleParams) (UpdateUserRoleTxResult, error) {
+	var result UpdateUserRoleTxResult
+
+	err := store.execAuditedTx(ctx, "user.role", func(q *Queries) (auditChange, error) {
+		before, err := q.GetUser(ctx, arg.Username)
+		if err != nil {
+			return auditChange{}, err
+		}
+		result.User, err = q.UpdateUserRole(ctx, arg)
+		if err != nil {
+			return auditChange{}, err
+		}
+		result.RevokedSessions, err = q.BlockUserSessions(ctx, arg.Username)
+		return auditChange{Entity: "user", Before: userAudit(before), After: userAudit(result.User)}, err
+	})
+	return result, err
+}
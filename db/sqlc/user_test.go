@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+// ** for testing we should create a user first
+func createRandomUser(t *testing.T, q *Queries) User {
+	hashedPassword, err := util.HashPassword(testRand.String(6))
+	require.NoError(t, err)
+
+	arg := CreateUserParams{
+		Username:       testRand.Owner(),
+		HashedPassword: hashedPassword,
+		FullName:       testRand.FullName(),
+		Email:          testRand.Email(),
+	}
+
+	user, err := q.CreateUser(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, user)
+
+	require.Equal(t, arg.Username, user.Username)
+	require.Equal(t, arg.HashedPassword, user.HashedPassword)
+	require.Equal(t, arg.FullName, user.FullName)
+	require.Equal(t, arg.Email, user.Email)
+	require.True(t, user.PasswordChangedAt.IsZero())
+	require.NotZero(t, user.CreatedAt)
+	require.Equal(t, UserRoleCustomer, user.Role)
+
+	return user
+}
+
+// ** Test Create User
+func TestCreateUser(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	createRandomUser(t, q)
+}
+
+// ** Test Get User
+func TestGetUser(t *testing.T) {
+	t.Parallel()
+	q := newTestStore(t).Queries
+
+	user1 := createRandomUser(t, q)
+	user2, err := q.GetUser(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.NotEmpty(t, user2)
+
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
+	require.Equal(t, user1.FullName, user2.FullName)
+	require.Equal(t, user1.Email, user2.Email)
+	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
+	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
+	require.Equal(t, user1.Role, user2.Role)
+}
+
+// ** Test Update User Role
+func TestUpdateUserRole(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	user1 := createRandomUser(t, q)
+
+	user2, err := store.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     UserRoleBanker,
+	})
+	require.NoError(t, err)
+	require.Equal(t, UserRoleBanker, user2.Role)
+	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
+
+	user3, err := q.GetUser(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.Equal(t, UserRoleBanker, user3.Role)
+}
+
+// ** Test Update User Role Tx
+func TestUpdateUserRoleTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	user := createRandomUser(t, q)
+	createRandomSession(t, q, user)
+	createRandomSession(t, q, user)
+
+	result, err := store.UpdateUserRoleTx(context.Background(), UpdateUserRoleParams{
+		Username: user.Username,
+		Role:     UserRoleAdmin,
+	})
+	require.NoError(t, err)
+	require.Equal(t, UserRoleAdmin, result.User.Role)
+	require.Equal(t, int64(2), result.RevokedSessions)
+
+	sessions, err := store.ListUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	// ** an unknown user changes nothing
+	_, err = store.UpdateUserRoleTx(context.Background(), UpdateUserRoleParams{
+		Username: testRand.Owner(),
+		Role:     UserRoleAdmin,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ** ErrInvalidSecretCode is returned when an email verification or password reset code
+// **  does not exist, was already used or has expired
+var ErrInvalidSecretCode = errors.New("invalid, used or expired code")
+
+// ** hashSecretCode is what is stored of an email verification or password reset code.
+// ** The codes are random, so a plain SHA-256 is enough to keep a leaked table from being used.
+func hashSecretCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// ** CreateUserTxParams contains the input parameters of the user creation transaction
+type CreateUserTxParams struct {
+	CreateUserParams
+	// ** SecretCode and ExpiredAt make up the email verification issued with the user; only the hash of the code is stored
+	SecretCode string
+	ExpiredAt  time.Time
+	// ** AfterCreate runs inside the transaction with its Queries; an error from it rolls back the user
+	AfterCreate func(q *Queries, user User, verifyEmail VerifyEmail) error
+}
+
+// ** CreateUserTxResult is the result of the user creation transaction
+type CreateUserTxResult struct {
+	User        User
+	VerifyEmail VerifyEmail
+}
+
+// ** CreateUserTx creates a user together with its email verification. It is audited as "user.create".
+// ** Use AfterCreate to enqueue the verification email, so no user is left that never gets one.
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		result.VerifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			Username:       result.User.Username,
+			Email:          result.User.Email,
+			SecretCodeHash: hashSecretCode(arg.SecretCode),
+			ExpiredAt:      arg.ExpiredAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, q, "user.create", auditChange{Entity: "user", After: userAudit(result.User)})
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(q, result.User, result.VerifyEmail)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ** VerifyEmailTxParams contains the input parameters of the email verification transaction
+type VerifyEmailTxParams struct {
+	EmailID    int64
+	SecretCode string
+}
+
+// ** VerifyEmailTxResult is the result of the email verification transaction
+type VerifyEmailTxResult struct {
+	User        User
+	VerifyEmail VerifyEmail
+}
+
+// ** VerifyEmailTx uses a verification code and marks the email of its user as verified.
+// ** A code sent to an address the user has since changed no longer counts.
+// ** It is audited as "user.verify_email".
+func (store *SQLStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.VerifyEmail, err = q.UseVerifyEmail(ctx, UseVerifyEmailParams{
+			ID:             arg.EmailID,
+			SecretCodeHash: hashSecretCode(arg.SecretCode),
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return fmt.Errorf("%w: email verification %d", ErrInvalidSecretCode, arg.EmailID)
+		}
+		if err != nil {
+			return err
+		}
+
+		before, err := q.GetUser(ctx, result.VerifyEmail.Username)
+		if err != nil {
+			return err
+		}
+		if before.Email != result.VerifyEmail.Email {
+			return fmt.Errorf("%w: email verification %d is for another address", ErrInvalidSecretCode, arg.EmailID)
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, before.Username)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "user.verify_email", auditChange{
+			Entity: "user",
+			Before: userAudit(before),
+			After:  userAudit(result.User),
+		})
+	})
+	return result, err
+}
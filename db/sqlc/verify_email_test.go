@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/util"
+)
+
+// ** createRandomVerifyEmail returns the verification and its code, which is not stored
+func createRandomVerifyEmail(t *testing.T, q *Queries, user User, expiredAt time.Time) (VerifyEmail, string) {
+	secretCode := testRand.String(32)
+	arg := CreateVerifyEmailParams{
+		Username:       user.Username,
+		Email:          user.Email,
+		SecretCodeHash: hashSecretCode(secretCode),
+		ExpiredAt:      expiredAt,
+	}
+
+	verifyEmail, err := q.CreateVerifyEmail(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, verifyEmail.ID)
+	require.Equal(t, arg.Username, verifyEmail.Username)
+	require.Equal(t, arg.Email, verifyEmail.Email)
+	require.Equal(t, arg.SecretCodeHash, verifyEmail.SecretCodeHash)
+	require.False(t, verifyEmail.IsUsed)
+	require.WithinDuration(t, arg.ExpiredAt, verifyEmail.ExpiredAt, time.Second)
+
+	return verifyEmail, secretCode
+}
+
+// ** Test Create User Tx
+func TestCreateUserTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	hashedPassword, err := util.HashPassword(testRand.String(6))
+	require.NoError(t, err)
+
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       testRand.Owner(),
+			HashedPassword: hashedPassword,
+			FullName:       testRand.FullName(),
+			Email:          testRand.Email(),
+		},
+		SecretCode: testRand.String(32),
+		ExpiredAt:  time.Now().Add(time.Hour),
+	}
+
+	var sent VerifyEmail
+	arg.AfterCreate = func(q *Queries, user User, verifyEmail VerifyEmail) error {
+		sent = verifyEmail
+		return nil
+	}
+	result, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, result.VerifyEmail, sent)
+	require.Equal(t, arg.Email, sent.Email)
+	// ** only the hash of the code is stored
+	require.Equal(t, hashSecretCode(arg.SecretCode), sent.SecretCodeHash)
+	require.NotContains(t, sent.SecretCodeHash, arg.SecretCode)
+
+	// ** a failing AfterCreate, e.g. the email could not be enqueued, leaves no user behind
+	arg.Username = testRand.Owner()
+	arg.Email = testRand.Email()
+	errSend := errors.New("cannot enqueue email")
+	arg.AfterCreate = func(q *Queries, user User, verifyEmail VerifyEmail) error { return errSend }
+	_, err = store.CreateUserTx(context.Background(), arg)
+	require.ErrorIs(t, err, errSend)
+
+	_, err = q.GetUser(context.Background(), arg.Username)
+	require.Error(t, err)
+}
+
+// ** Test Verify Email Tx
+func TestVerifyEmailTx(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	user := createRandomUser(t, q)
+	verifyEmail, secretCode := createRandomVerifyEmail(t, q, user, time.Now().Add(time.Hour))
+
+	_, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: "wrong",
+	})
+	require.ErrorIs(t, err, ErrInvalidSecretCode)
+
+	// ** neither is the stored hash
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCodeHash,
+	})
+	require.ErrorIs(t, err, ErrInvalidSecretCode)
+
+	result, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: secretCode,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	require.True(t, result.VerifyEmail.IsUsed)
+
+	// ** a code can be used only once
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: secretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidSecretCode)
+}
+
+// ** Test Verify Email Tx Expired
+func TestVerifyEmailTxExpired(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	user := createRandomUser(t, q)
+	verifyEmail, secretCode := createRandomVerifyEmail(t, q, user, time.Now().Add(-time.Minute))
+
+	_, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailID:    verifyEmail.ID,
+		SecretCode: secretCode,
+	})
+	require.ErrorIs(t, err, ErrInvalidSecretCode)
+
+	user, err = q.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, user.IsEmailVerified)
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ** ErrConcurrentModification is returned by conditional updates when the account
+// ** changed since the caller read it, i.e. its version is not the expected one
+var ErrConcurrentModification = errors.New("account was modified concurrently")
+
+// ** UpdateAccountIfUnchanged sets the balance only if the account is still at the expected version
+func (store *SQLStore) UpdateAccountIfUnchanged(ctx context.Context, arg UpdateAccountIfVersionParams) (Account, error) {
+	var account Account
+	err := store.execTx(ctx, func(q *Queries) error {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if err = checkVersion(before, arg.ExpectedVersion); err != nil {
+			return err
+		}
+
+		account, err = q.UpdateAccountIfVersion(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, "account.update", auditChange{
+			Entity:   "account",
+			EntityID: arg.ID,
+			Before:   before,
+			After:    account,
+		})
+	})
+	return account, err
+}
+
+// ** checkVersion returns ErrConcurrentModification unless the account is at the expected version
+func checkVersion(account Account, expectedVersion int64) error {
+	if account.Version != expectedVersion {
+		return fmt.Errorf("%w: account %d is at version %d, expected %d", ErrConcurrentModification, account.ID, account.Version, expectedVersion)
+	}
+	return nil
+}
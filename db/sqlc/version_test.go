@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** Test Update Account If Unchanged
+func TestUpdateAccountIfUnchanged(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+
+	arg := UpdateAccountIfVersionParams{
+		ID:              account1.ID,
+		Balance:         testRand.Money(),
+		ExpectedVersion: account1.Version,
+	}
+	account2, err := store.UpdateAccountIfUnchanged(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Balance, account2.Balance)
+	require.Equal(t, account1.Version+1, account2.Version)
+
+	// ** a second writer that read the account before the first update loses
+	_, err = store.UpdateAccountIfUnchanged(context.Background(), arg)
+	require.ErrorIs(t, err, ErrConcurrentModification)
+
+	account3, err := q.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, account3.Balance)
+	require.Equal(t, account2.Version, account3.Version)
+
+	arg.ID = -1
+	_, err = store.UpdateAccountIfUnchanged(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+// ** Test Change Account Status With Version
+func TestChangeAccountStatusWithVersion(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+
+	_, err := store.ChangeAccountStatus(context.Background(), account1.ID, AccountStatusFrozen, account1.Version+1)
+	require.ErrorIs(t, err, ErrConcurrentModification)
+
+	account2, err := store.ChangeAccountStatus(context.Background(), account1.ID, AccountStatusFrozen, account1.Version)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, account2.Status)
+	require.Equal(t, account1.Version+1, account2.Version)
+}
+
+// ** Test Transfer Bumps Versions
+func TestTransferTxBumpsVersion(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(t)
+	q := store.Queries
+
+	account1 := createRandomAccount(t, q)
+	account2 := createRandomAccountIn(t, q, account1.Currency)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Version+1, result.FromAccount.Version)
+	require.Equal(t, account2.Version+1, result.ToAccount.Version)
+}
+
+func TestCheckVersion(t *testing.T) {
+	account := Account{ID: 1, Version: 3}
+	require.NoError(t, checkVersion(account, 3))
+	require.ErrorIs(t, checkVersion(account, 2), ErrConcurrentModification)
+}
@@ -0,0 +1,272 @@
+// ** Package fixtures loads declarative YAML test data through the store, so the rows it creates are
+// **  consistent: balances are booked as entries and transfers go through TransferTx.
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+	"github.com/techschool/simplebank/util"
+	"gopkg.in/yaml.v3"
+)
+
+// ** ErrInvalidFixtures is returned when a fixture file cannot be parsed or refers to unknown names
+var ErrInvalidFixtures = errors.New("invalid fixtures")
+
+// ** DefaultPassword is the password of fixture users that do not set one
+const DefaultPassword = "secret"
+
+// ** Fixtures is the content of a fixture file:
+// **
+// **	users:
+// **	  - username: alice
+// **	    email: alice@example.com
+// **	    role: banker
+// **	accounts:
+// **	  - name: alice_eur
+// **	    owner: alice
+// **	    balance: 100.00 EUR
+// **	transfers:
+// **	  - from: alice_eur
+// **	    to: bob_eur
+// **	    amount: 12.50 EUR
+type Fixtures struct {
+	Users     []User     `yaml:"users"`
+	Accounts  []Account  `yaml:"accounts"`
+	Transfers []Transfer `yaml:"transfers"`
+}
+
+// ** User is created first. Missing full names and emails are derived from the username.
+type User struct {
+	Username      string      `yaml:"username"`
+	FullName      string      `yaml:"full_name"`
+	Email         string      `yaml:"email"`
+	Password      string      `yaml:"password"`
+	Role          db.UserRole `yaml:"role"`
+	EmailVerified bool        `yaml:"email_verified"`
+}
+
+// ** Account is opened empty for a fixture user, then credited its balance with an adjustment entry
+type Account struct {
+	Name    string `yaml:"name"`
+	Owner   string `yaml:"owner"`
+	Balance string `yaml:"balance"`
+}
+
+// ** Transfer moves money between two fixture accounts, in file order after all balances are set
+type Transfer struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Amount string `yaml:"amount"`
+}
+
+// ** Set is what loading fixtures created, by name
+type Set struct {
+	Users     map[string]db.User
+	Accounts  map[string]db.Account // ** with the balances after all transfers
+	Transfers []db.Transfer
+}
+
+// ** Store is the part of db.Store fixtures are loaded through
+type Store interface {
+	CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error)
+	UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error)
+	VerifyUserEmail(ctx context.Context, username string) (db.User, error)
+	CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error)
+	GetAccount(ctx context.Context, id int64) (db.Account, error)
+	AdjustBalanceTx(ctx context.Context, arg db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error)
+	TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error)
+}
+
+// ** Parse reads fixtures and checks that every name they refer to is declared
+func Parse(r io.Reader) (Fixtures, error) {
+	var fixtures Fixtures
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixtures); err != nil && !errors.Is(err, io.EOF) {
+		return Fixtures{}, fmt.Errorf("%w: %v", ErrInvalidFixtures, err)
+	}
+	return fixtures, fixtures.validate()
+}
+
+// ** ParseFile reads fixtures from a file
+func ParseFile(path string) (Fixtures, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Fixtures{}, err
+	}
+	defer file.Close()
+
+	fixtures, err := Parse(file)
+	if err != nil {
+		return Fixtures{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// ** LoadFile parses a fixture file and loads it through the store
+func LoadFile(ctx context.Context, store Store, path string) (Set, error) {
+	fixtures, err := ParseFile(path)
+	if err != nil {
+		return Set{}, err
+	}
+	return fixtures.Load(ctx, store)
+}
+
+func (fixtures Fixtures) validate() error {
+	users := make(map[string]bool)
+	for _, user := range fixtures.Users {
+		if user.Username == "" || users[user.Username] {
+			return fmt.Errorf("%w: missing or duplicate username %q", ErrInvalidFixtures, user.Username)
+		}
+		users[user.Username] = true
+	}
+
+	accounts := make(map[string]money.Money)
+	for _, account := range fixtures.Accounts {
+		if account.Name == "" || accounts[account.Name].Currency != "" {
+			return fmt.Errorf("%w: missing or duplicate account name %q", ErrInvalidFixtures, account.Name)
+		}
+		if !users[account.Owner] {
+			return fmt.Errorf("%w: account %q is owned by unknown user %q", ErrInvalidFixtures, account.Name, account.Owner)
+		}
+		balance, err := money.Parse(account.Balance)
+		if err != nil {
+			return fmt.Errorf("%w: balance of account %q: %v", ErrInvalidFixtures, account.Name, err)
+		}
+		accounts[account.Name] = balance
+	}
+
+	for i, transfer := range fixtures.Transfers {
+		from, fromOK := accounts[transfer.From]
+		to, toOK := accounts[transfer.To]
+		if !fromOK || !toOK {
+			return fmt.Errorf("%w: transfer %d is between unknown accounts %q and %q", ErrInvalidFixtures, i+1, transfer.From, transfer.To)
+		}
+		amount, err := money.Parse(transfer.Amount)
+		if err != nil {
+			return fmt.Errorf("%w: amount of transfer %d: %v", ErrInvalidFixtures, i+1, err)
+		}
+		if amount.Currency != from.Currency || amount.Currency != to.Currency {
+			return fmt.Errorf("%w: transfer %d of %s is between %s and %s accounts", ErrInvalidFixtures, i+1, amount, from.Currency, to.Currency)
+		}
+	}
+	return nil
+}
+
+// ** Load creates users, accounts and transfers in this order.
+// ** It stops at the first error; use a store of NewTxStore to roll back what was created before.
+func (fixtures Fixtures) Load(ctx context.Context, store Store) (Set, error) {
+	if err := fixtures.validate(); err != nil {
+		return Set{}, err
+	}
+	set := Set{
+		Users:    make(map[string]db.User),
+		Accounts: make(map[string]db.Account),
+	}
+
+	for _, fixture := range fixtures.Users {
+		user, err := loadUser(ctx, store, fixture)
+		if err != nil {
+			return Set{}, fmt.Errorf("user %q: %w", fixture.Username, err)
+		}
+		set.Users[user.Username] = user
+	}
+
+	for _, fixture := range fixtures.Accounts {
+		account, err := loadAccount(ctx, store, fixture)
+		if err != nil {
+			return Set{}, fmt.Errorf("account %q: %w", fixture.Name, err)
+		}
+		set.Accounts[fixture.Name] = account
+	}
+
+	for i, fixture := range fixtures.Transfers {
+		amount, err := money.Parse(fixture.Amount)
+		if err != nil {
+			return Set{}, err
+		}
+		arg, err := db.NewTransferTxParams(set.Accounts[fixture.From].ID, set.Accounts[fixture.To].ID, amount)
+		if err != nil {
+			return Set{}, fmt.Errorf("transfer %d: %w", i+1, err)
+		}
+		result, err := store.TransferTx(ctx, arg)
+		if err != nil {
+			return Set{}, fmt.Errorf("transfer %d: %w", i+1, err)
+		}
+		set.Transfers = append(set.Transfers, result.Transfer)
+	}
+
+	// ** transfers changed the balances
+	for name, account := range set.Accounts {
+		account, err := store.GetAccount(ctx, account.ID)
+		if err != nil {
+			return Set{}, err
+		}
+		set.Accounts[name] = account
+	}
+	return set, nil
+}
+
+func loadUser(ctx context.Context, store Store, fixture User) (db.User, error) {
+	password := fixture.Password
+	if password == "" {
+		password = DefaultPassword
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	arg := db.CreateUserParams{
+		Username:       fixture.Username,
+		HashedPassword: hashedPassword,
+		FullName:       fixture.FullName,
+		Email:          fixture.Email,
+	}
+	if arg.FullName == "" {
+		arg.FullName = fixture.Username
+	}
+	if arg.Email == "" {
+		arg.Email = fixture.Username + "@example.com"
+	}
+
+	user, err := store.CreateUser(ctx, arg)
+	if err != nil {
+		return db.User{}, err
+	}
+	if fixture.Role != "" && fixture.Role != user.Role {
+		user, err = store.UpdateUserRole(ctx, db.UpdateUserRoleParams{Username: user.Username, Role: fixture.Role})
+		if err != nil {
+			return db.User{}, err
+		}
+	}
+	if fixture.EmailVerified {
+		user, err = store.VerifyUserEmail(ctx, user.Username)
+	}
+	return user, err
+}
+
+func loadAccount(ctx context.Context, store Store, fixture Account) (db.Account, error) {
+	balance, err := money.Parse(fixture.Balance)
+	if err != nil {
+		return db.Account{}, err
+	}
+
+	account, err := store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    fixture.Owner,
+		Balance:  0,
+		Currency: balance.Currency,
+	})
+	if err != nil || balance.IsZero() {
+		return account, err
+	}
+
+	result, err := store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{AccountID: account.ID, Amount: balance})
+	return result.Account, err
+}
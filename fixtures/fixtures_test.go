@@ -0,0 +1,93 @@
+package fixtures
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/util"
+)
+
+var bankFile = filepath.Join("testdata", "bank.yaml")
+
+func TestParseFile(t *testing.T) {
+	fixtures, err := ParseFile(bankFile)
+	require.NoError(t, err)
+	require.Len(t, fixtures.Users, 3)
+	require.Equal(t, db.UserRoleBanker, fixtures.Users[2].Role)
+	require.True(t, fixtures.Users[0].EmailVerified)
+	require.Len(t, fixtures.Accounts, 3)
+	require.Equal(t, Transfer{From: "alice_eur", To: "bob_eur", Amount: "30.00 EUR"}, fixtures.Transfers[0])
+}
+
+func TestParseInvalid(t *testing.T) {
+	valid, err := os.ReadFile(bankFile)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		replace [2]string
+	}{
+		{"unknown field", [2]string{"full_name:", "fullname:"}},
+		{"duplicate user", [2]string{"username: bob", "username: alice"}},
+		{"unknown owner", [2]string{"owner: bob", "owner: dave"}},
+		{"duplicate account", [2]string{"name: alice_usd", "name: alice_eur"}},
+		{"invalid balance", [2]string{"100.00 EUR", "100.001 EUR"}},
+		{"unknown account", [2]string{"to: bob_eur", "to: bob_usd"}},
+		{"currency mismatch", [2]string{"to: bob_eur", "to: alice_usd"}},
+		{"invalid amount", [2]string{"30.00 EUR", "30 euros"}},
+		{"malformed yaml", [2]string{"users:", "users: ["}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := strings.Replace(string(valid), tc.replace[0], tc.replace[1], 1)
+			require.NotEqual(t, string(valid), content)
+
+			_, err := Parse(strings.NewReader(content))
+			require.ErrorIs(t, err, ErrInvalidFixtures)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+	store := TxStore(t, testDB)
+	set := MustLoadFile(t, store, bankFile)
+
+	alice := set.Users["alice"]
+	require.True(t, alice.IsEmailVerified)
+	require.NoError(t, util.CheckPassword(DefaultPassword, alice.HashedPassword))
+	require.Equal(t, db.UserRoleBanker, set.Users["carol"].Role)
+	require.Equal(t, "bob@example.com", set.Users["bob"].Email)
+
+	require.Equal(t, int64(7050), set.Accounts["alice_eur"].Balance)
+	require.Equal(t, int64(0), set.Accounts["alice_usd"].Balance)
+	require.Equal(t, int64(5000), set.Accounts["bob_eur"].Balance)
+	require.Len(t, set.Transfers, 2)
+
+	// ** balances are backed by entries
+	for _, account := range set.Accounts {
+		total, err := store.SumAccountEntriesSince(context.Background(), db.SumAccountEntriesSinceParams{
+			AccountID: account.ID,
+			Since:     time.Time{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, account.Balance, total)
+	}
+}
+
+// ** loads the same usernames as TestLoadFile, so one waits for the other to roll back
+func TestLoadFileIsolated(t *testing.T) {
+	t.Parallel()
+	store := TxStore(t, testDB)
+	set := MustLoadFile(t, store, bankFile)
+
+	_, err := db.New(testDB).GetAccount(context.Background(), set.Accounts["alice_eur"].ID)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+}
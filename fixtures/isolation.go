@@ -0,0 +1,41 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** TxStore begins a transaction on conn and returns a store running inside it, see db.NewTxStore.
+// ** The transaction is rolled back when the test ends, so the test leaves no rows behind and
+// **  parallel tests do not see each other's data. Parallel tests inserting the same unique
+// **  values, or writing the audit log, wait for each other to end.
+func TxStore(t testing.TB, conn *pgxpool.Pool) db.Store {
+	t.Helper()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("cannot begin test transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(context.Background()); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			t.Errorf("cannot roll back test transaction: %v", err)
+		}
+	})
+	return db.NewTxStore(tx)
+}
+
+// ** MustLoadFile loads a fixture file through the store and fails the test on error
+func MustLoadFile(t testing.TB, store Store, path string) Set {
+	t.Helper()
+
+	set, err := LoadFile(context.Background(), store, path)
+	if err != nil {
+		t.Fatalf("cannot load fixtures: %v", err)
+	}
+	return set
+}
@@ -0,0 +1,74 @@
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ** Supported day count conventions
+const (
+	DayCountActual365    = "ACT/365"
+	DayCountActual360    = "ACT/360"
+	DayCountActualActual = "ACT/ACT"
+	DayCount30E360       = "30E/360"
+)
+
+// ** micros per minor unit, accruals are kept with this precision
+const micros = 1000000
+
+const basisPoints = 10000
+
+// ** dayFraction returns the fraction of a year one day (day to day+1) counts for
+func dayFraction(convention string, day time.Time) (numerator int64, denominator int64, err error) {
+	switch convention {
+	case DayCountActual365:
+		return 1, 365, nil
+	case DayCountActual360:
+		return 1, 360, nil
+	case DayCountActualActual:
+		year := day.Year()
+		if time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay() == 366 {
+			return 1, 366, nil
+		}
+		return 1, 365, nil
+	case DayCount30E360:
+		return days30E360(day, day.AddDate(0, 0, 1)), 360, nil
+	}
+	return 0, 0, fmt.Errorf("unsupported day count convention %q", convention)
+}
+
+// ** days30E360 counts days between two dates as if every month had 30 days
+func days30E360(start, end time.Time) int64 {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 {
+		d2 = 30
+	}
+	return int64(360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1)
+}
+
+// ** DailyInterest returns the interest, in millionths of a minor unit, that a balance
+// **  earns on one day at an annual rate given in basis points. Non-positive balances earn nothing.
+func DailyInterest(balance int64, annualRateBps int64, convention string, day time.Time) (int64, error) {
+	numerator, denominator, err := dayFraction(convention, day)
+	if err != nil {
+		return 0, err
+	}
+	if balance <= 0 || annualRateBps <= 0 {
+		return 0, nil
+	}
+
+	// ** balance * rate / 10000 * numerator / denominator * 1e6, without overflowing int64 on the way
+	amount := new(big.Int).SetInt64(balance)
+	amount.Mul(amount, big.NewInt(annualRateBps))
+	amount.Mul(amount, big.NewInt(numerator))
+	amount.Mul(amount, big.NewInt(micros))
+	amount.Quo(amount, big.NewInt(basisPoints*denominator))
+	if !amount.IsInt64() {
+		return 0, fmt.Errorf("interest on balance %d overflows", balance)
+	}
+	return amount.Int64(), nil
+}
@@ -0,0 +1,57 @@
+package interest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDailyInterest(t *testing.T) {
+	day := date(2023, time.March, 15)
+
+	// ** 1000.00 at 3.65% for one day ACT/365 is exactly 0.10
+	amount, err := DailyInterest(100000, 365, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(10*micros), amount)
+
+	// ** 3.60% ACT/360 earns the same
+	amount, err = DailyInterest(100000, 360, DayCountActual360, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(10*micros), amount)
+
+	// ** ACT/ACT divides by 366 in leap years
+	amount, err = DailyInterest(100000, 366, DayCountActualActual, date(2024, time.June, 1))
+	require.NoError(t, err)
+	require.Equal(t, int64(10*micros), amount)
+
+	// ** no interest on empty or negative balances
+	amount, err = DailyInterest(-100000, 365, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Zero(t, amount)
+
+	_, err = DailyInterest(100000, 365, "BUS/252", day)
+	require.Error(t, err)
+}
+
+func TestDays30E360(t *testing.T) {
+	// ** every month counts 30 days, whatever its length
+	for _, month := range []time.Month{time.January, time.February, time.April} {
+		var total int64
+		start := date(2023, month, 1)
+		for day := start; day.Before(start.AddDate(0, 1, 0)); day = day.AddDate(0, 0, 1) {
+			numerator, denominator, err := dayFraction(DayCount30E360, day)
+			require.NoError(t, err)
+			require.Equal(t, int64(360), denominator)
+			total += numerator
+		}
+		require.Equal(t, int64(30), total, month.String())
+	}
+
+	// ** the 31st does not count
+	require.Zero(t, days30E360(date(2023, time.January, 30), date(2023, time.January, 31)))
+}
@@ -0,0 +1,177 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** Store is the part of db.Store the interest job needs
+type Store interface {
+	GetAccountBalanceBefore(ctx context.Context, arg db.GetAccountBalanceBeforeParams) (int64, error)
+	ListInterestBearingAccounts(ctx context.Context) ([]db.ListInterestBearingAccountsRow, error)
+	CreateInterestAccrual(ctx context.Context, arg db.CreateInterestAccrualParams) (int64, error)
+	PostInterestTx(ctx context.Context, arg db.PostInterestTxParams) (db.PostInterestTxResult, error)
+}
+
+// ** Job accrues interest daily and posts it monthly. Both steps are idempotent,
+// **  so running the job twice for the same day or month has no further effect.
+type Job struct {
+	store Store
+}
+
+// ** NewJob creates a new interest job
+func NewJob(store Store) *Job {
+	return &Job{store: store}
+}
+
+// ** startOfDay truncates a time to midnight UTC
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ** endOfDayBalance derives the balance at the end of a day from the current balance
+// **  minus everything booked after that day. Both are read by a single query, so a transfer
+// **  committing in between cannot be counted in one and missed in the other.
+func (job *Job) endOfDayBalance(ctx context.Context, accountID int64, day time.Time) (int64, error) {
+	return job.store.GetAccountBalanceBefore(ctx, db.GetAccountBalanceBeforeParams{
+		Before:    day.AddDate(0, 0, 1),
+		AccountID: accountID,
+	})
+}
+
+// ** dailyAccrual is the interest of a day in millionths of a minor unit. An overdrawn account
+// **  is charged the overdraft rate of its plan, which makes the accrual negative.
+func dailyAccrual(balance int64, plan db.ListInterestBearingAccountsRow, day time.Time) (int64, error) {
+	if balance >= 0 {
+		return DailyInterest(balance, plan.AnnualRateBps, plan.DayCount, day)
+	}
+	charge, err := DailyInterest(-balance, plan.OverdraftRateBps, plan.DayCount, day)
+	return -charge, err
+}
+
+// ** AccrueDay stores the interest every interest bearing account earned or owes on the given day.
+// ** A frozen account accrues nothing while it is frozen. It returns the number of new accruals.
+// ** Balances are read on the primary: an accrual is stored once, so it must not miss transfers
+// **  a replica has not caught up with yet.
+func (job *Job) AccrueDay(ctx context.Context, day time.Time) (int, error) {
+	ctx = db.ForcePrimary(ctx)
+	day = startOfDay(day)
+
+	accounts, err := job.store.ListInterestBearingAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	accrued := 0
+	for _, account := range accounts {
+		if account.Status != db.AccountStatusActive {
+			continue
+		}
+
+		balance, err := job.endOfDayBalance(ctx, account.AccountID, day)
+		if err != nil {
+			return accrued, err
+		}
+
+		amount, err := dailyAccrual(balance, account, day)
+		if err != nil {
+			return accrued, err
+		}
+
+		n, err := job.store.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+			AccountID:      account.AccountID,
+			InterestPlanID: account.InterestPlanID,
+			AccrualDate:    day,
+			Balance:        balance,
+			AmountMicros:   amount,
+		})
+		if err != nil {
+			return accrued, err
+		}
+		accrued += int(n)
+	}
+	return accrued, nil
+}
+
+// ** PostMonth posts the interest accrued during the month containing the given day.
+// ** An account that cannot be posted to right now, because it or the house account is frozen or
+// **  the paying side lacks the funds, is skipped, so running PostMonth again once that changed
+// **  posts the month. It returns the number of new postings.
+func (job *Job) PostMonth(ctx context.Context, month time.Time) (int, error) {
+	month = startOfDay(month)
+	periodStart := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+
+	accounts, err := job.store.ListInterestBearingAccounts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, account := range accounts {
+		result, err := job.store.PostInterestTx(ctx, db.PostInterestTxParams{
+			AccountID:         account.AccountID,
+			InterestAccountID: account.InterestAccountID,
+			PeriodStart:       periodStart,
+			PeriodEnd:         periodEnd,
+		})
+		var notActive *db.AccountNotActiveError
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.As(err, &notActive) {
+			log.Printf("interest: cannot post %s for account %d: %v", periodStart.Format("2006-01"), account.AccountID, err)
+			continue
+		}
+		if err != nil {
+			return posted, err
+		}
+		if !result.AlreadyPosted {
+			posted++
+		}
+	}
+	return posted, nil
+}
+
+// ** Run accrues interest for the day before now and, on the first day of a month,
+// **  posts the interest of the previous month
+func (job *Job) Run(ctx context.Context, now time.Time) error {
+	yesterday := startOfDay(now).AddDate(0, 0, -1)
+
+	accrued, err := job.AccrueDay(ctx, yesterday)
+	if err != nil {
+		return err
+	}
+	log.Printf("interest: %d accruals for %s", accrued, yesterday.Format("2006-01-02"))
+
+	if now.UTC().Day() != 1 {
+		return nil
+	}
+
+	posted, err := job.PostMonth(ctx, yesterday)
+	if err != nil {
+		return err
+	}
+	log.Printf("interest: %d postings for %s", posted, yesterday.Format("2006-01"))
+	return nil
+}
+
+// ** Start runs the job once and then on every tick of interval until ctx is cancelled
+func (job *Job) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx, time.Now()); err != nil {
+			log.Printf("interest: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** fakeStore keeps accruals and postings in memory with the same uniqueness rules as the database
+type fakeStore struct {
+	accounts map[int64]db.Account
+	entries  []db.Entry
+	plans    []db.ListInterestBearingAccountsRow
+	accruals map[int64]map[time.Time]db.CreateInterestAccrualParams
+	postings map[int64]map[time.Time]int64
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		accounts: map[int64]db.Account{
+			1: {ID: 1, Balance: 100000, Status: db.AccountStatusActive},
+			2: {ID: 2, Balance: 365000, Status: db.AccountStatusActive},
+		},
+		plans: []db.ListInterestBearingAccountsRow{
+			{AccountID: 2, InterestPlanID: 1, AnnualRateBps: 100, DayCount: DayCountActual365, InterestAccountID: 1},
+		},
+		accruals: make(map[int64]map[time.Time]db.CreateInterestAccrualParams),
+		postings: make(map[int64]map[time.Time]int64),
+	}
+}
+
+// ** errReplicaRead is returned for a balance read that could go to a read replica
+var errReplicaRead = errors.New("balance read from the replica")
+
+func (f *fakeStore) GetAccountBalanceBefore(ctx context.Context, arg db.GetAccountBalanceBeforeParams) (int64, error) {
+	if !db.IsPrimaryForced(ctx) {
+		return 0, errReplicaRead
+	}
+	balance := f.accounts[arg.AccountID].Balance
+	for _, entry := range f.entries {
+		if entry.AccountID == arg.AccountID && !entry.CreatedAt.Before(arg.Before) {
+			balance -= entry.Amount
+		}
+	}
+	return balance, nil
+}
+
+// ** like the database, closed accounts are left out and the others carry their status
+func (f *fakeStore) ListInterestBearingAccounts(ctx context.Context) ([]db.ListInterestBearingAccountsRow, error) {
+	var plans []db.ListInterestBearingAccountsRow
+	for _, plan := range f.plans {
+		plan.Status = f.accounts[plan.AccountID].Status
+		if plan.Status != db.AccountStatusClosed {
+			plans = append(plans, plan)
+		}
+	}
+	return plans, nil
+}
+
+func (f *fakeStore) CreateInterestAccrual(ctx context.Context, arg db.CreateInterestAccrualParams) (int64, error) {
+	if f.accruals[arg.AccountID] == nil {
+		f.accruals[arg.AccountID] = make(map[time.Time]db.CreateInterestAccrualParams)
+	}
+	if _, ok := f.accruals[arg.AccountID][arg.AccrualDate]; ok {
+		return 0, nil
+	}
+	f.accruals[arg.AccountID][arg.AccrualDate] = arg
+	return 1, nil
+}
+
+func (f *fakeStore) PostInterestTx(ctx context.Context, arg db.PostInterestTxParams) (db.PostInterestTxResult, error) {
+	if f.postings[arg.AccountID] == nil {
+		f.postings[arg.AccountID] = make(map[time.Time]int64)
+	}
+	if amount, ok := f.postings[arg.AccountID][arg.PeriodStart]; ok {
+		return db.PostInterestTxResult{AlreadyPosted: true, Posting: db.InterestPosting{Amount: amount}}, nil
+	}
+
+	for _, id := range []int64{arg.InterestAccountID, arg.AccountID} {
+		if status := f.accounts[id].Status; status != db.AccountStatusActive {
+			return db.PostInterestTxResult{}, &db.AccountNotActiveError{AccountID: id, Status: status}
+		}
+	}
+
+	var micros int64
+	for day, accrual := range f.accruals[arg.AccountID] {
+		if !day.Before(arg.PeriodStart) && day.Before(arg.PeriodEnd) {
+			micros += accrual.AmountMicros
+		}
+	}
+	// ** like the database, interest is only booked when the house can pay it,
+	// **  overdraft interest when the account can
+	if house := f.accounts[arg.InterestAccountID]; micros > 0 && micros/1000000 > house.Available() {
+		return db.PostInterestTxResult{}, fmt.Errorf("%w: account %d", db.ErrInsufficientFunds, arg.InterestAccountID)
+	}
+	if account := f.accounts[arg.AccountID]; micros < 0 && -micros/1000000 > account.Available() {
+		return db.PostInterestTxResult{}, fmt.Errorf("%w: account %d", db.ErrInsufficientFunds, arg.AccountID)
+	}
+	f.postings[arg.AccountID][arg.PeriodStart] = micros / 1000000
+	return db.PostInterestTxResult{Posting: db.InterestPosting{Amount: micros / 1000000}}, nil
+}
+
+func TestJobAccrueDay(t *testing.T) {
+	store := newFakeStore()
+	job := NewJob(store)
+	day := date(2023, time.March, 10)
+
+	// ** a deposit made after the day does not count towards its end of day balance
+	store.accounts[2] = db.Account{ID: 2, Balance: 465000, Status: db.AccountStatusActive}
+	store.entries = append(store.entries, db.Entry{AccountID: 2, Amount: 100000, CreatedAt: day.Add(30 * time.Hour)})
+
+	n, err := job.AccrueDay(context.Background(), day.Add(15*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	accrual := store.accruals[2][day]
+	require.Equal(t, int64(365000), accrual.Balance)
+	// ** 3650.00 at 1% ACT/365 earns 0.10 a day
+	require.Equal(t, int64(10*micros), accrual.AmountMicros)
+
+	// ** accruing the same day again is a no-op
+	n, err = job.AccrueDay(context.Background(), day)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestJobReadsPrimary(t *testing.T) {
+	store := newFakeStore()
+	job := NewJob(store)
+
+	// ** the fake refuses balance reads that are not forced to the primary
+	_, err := store.GetAccountBalanceBefore(context.Background(), db.GetAccountBalanceBeforeParams{AccountID: 2})
+	require.ErrorIs(t, err, errReplicaRead)
+
+	n, err := job.AccrueDay(context.Background(), date(2023, time.March, 10))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestJobRun(t *testing.T) {
+	store := newFakeStore()
+	job := NewJob(store)
+
+	for day := date(2023, time.March, 2); !day.After(date(2023, time.April, 1)); day = day.AddDate(0, 0, 1) {
+		require.NoError(t, job.Run(context.Background(), day.Add(2*time.Hour)))
+	}
+
+	// ** March 1st to 31st accrued, then posted on April 1st
+	require.Len(t, store.accruals[2], 31)
+	require.Equal(t, int64(310), store.postings[2][date(2023, time.March, 1)])
+
+	// ** running again on the same day posts nothing new
+	posted, err := job.PostMonth(context.Background(), date(2023, time.March, 31))
+	require.NoError(t, err)
+	require.Zero(t, posted)
+}
+
+func TestJobOverdraftInterest(t *testing.T) {
+	store := newFakeStore()
+	job := NewJob(store)
+	day := date(2023, time.March, 10)
+
+	store.accounts[3] = db.Account{ID: 3, Balance: -365000, OverdraftLimit: 365000, Status: db.AccountStatusActive}
+	store.plans = append(store.plans, db.ListInterestBearingAccountsRow{
+		AccountID: 3, InterestPlanID: 1, AnnualRateBps: 100, OverdraftRateBps: 1000, DayCount: DayCountActual365, InterestAccountID: 1,
+	})
+
+	n, err := job.AccrueDay(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	// ** overdrawn by 3650.00 at 10% ACT/365 owes 1.00 a day
+	accrual := store.accruals[3][day]
+	require.Equal(t, int64(-365000), accrual.Balance)
+	require.Equal(t, int64(-100*micros), accrual.AmountMicros)
+
+	// ** the overdraft is used up, so the interest cannot be posted yet; the other account still is
+	posted, err := job.PostMonth(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 1, posted)
+	require.NotContains(t, store.postings[3], date(2023, time.March, 1))
+
+	// ** once the overdraft allows it, posting the month again books it
+	store.accounts[3] = db.Account{ID: 3, Balance: -365000, OverdraftLimit: 400000, Status: db.AccountStatusActive}
+	posted, err = job.PostMonth(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 1, posted)
+	require.Equal(t, int64(-100), store.postings[3][date(2023, time.March, 1)])
+}
+
+func TestJobHouseWithoutFunds(t *testing.T) {
+	store := newFakeStore()
+	job := NewJob(store)
+	day := date(2023, time.March, 10)
+
+	n, err := job.AccrueDay(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// ** a house account that cannot pay the interest is skipped instead of going below zero
+	store.accounts[1] = db.Account{ID: 1, Balance: 0, Status: db.AccountStatusActive}
+	posted, err := job.PostMonth(context.Background(), day)
+	require.NoError(t, err)
+	require.Zero(t, posted)
+	require.NotContains(t, store.postings[2], date(2023, time.March, 1))
+
+	// ** once funded, posting the month again books it
+	store.accounts[1] = db.Account{ID: 1, Balance: 10, Status: db.AccountStatusActive}
+	posted, err = job.PostMonth(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 1, posted)
+	require.Equal(t, int64(10), store.postings[2][date(2023, time.March, 1)])
+}
+
+func TestJobFrozenAccount(t *testing.T) {
+	store := newFakeStore()
+	job := NewJob(store)
+	day := date(2023, time.March, 10)
+
+	n, err := job.AccrueDay(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// ** a frozen account accrues nothing and its month is not posted while it is frozen
+	account := store.accounts[2]
+	account.Status = db.AccountStatusFrozen
+	store.accounts[2] = account
+
+	n, err = job.AccrueDay(context.Background(), day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	posted, err := job.PostMonth(context.Background(), day)
+	require.NoError(t, err)
+	require.Zero(t, posted)
+
+	// ** once unfrozen, the day it accrued before is posted
+	account.Status = db.AccountStatusActive
+	store.accounts[2] = account
+
+	posted, err = job.PostMonth(context.Background(), day)
+	require.NoError(t, err)
+	require.Equal(t, 1, posted)
+	require.Equal(t, int64(10), store.postings[2][date(2023, time.March, 1)])
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/interest"
+	"github.com/techschool/simplebank/snapshot"
+)
+
+// ** jobInterval is how often the periodic jobs run. They are idempotent, so running
+// **  them more often than daily only catches up sooner after a restart or a failed run.
+const jobInterval = time.Hour
+
+// ** periodicJob runs until ctx is cancelled, like interest.Job and snapshot.Job
+type periodicJob interface {
+	Start(ctx context.Context, interval time.Duration)
+}
+
+// ** newJobs creates the periodic jobs the server runs next to the task processor
+func newJobs(store db.Store) []periodicJob {
+	return []periodicJob{
+		interest.NewJob(store),
+		snapshot.NewJob(store),
+	}
+}
+
+// ** startJobs starts every job in the background until ctx is cancelled.
+// ** Pass the returned group to waitJobs to let them stop.
+func startJobs(ctx context.Context, interval time.Duration, jobs []periodicJob) *sync.WaitGroup {
+	wg := &sync.WaitGroup{}
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job periodicJob) {
+			defer wg.Done()
+			job.Start(ctx, interval)
+		}(job)
+	}
+	return wg
+}
+
+// ** waitJobs waits for the jobs of wg to return, at most until ctx is done
+func waitJobs(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/techschool/simplebank/interest"
+	"github.com/techschool/simplebank/snapshot"
+)
+
+// ** fakeJob reports the interval it was started with and returns when ctx is cancelled or release is closed
+type fakeJob struct {
+	started chan time.Duration
+	release chan struct{}
+}
+
+func newFakeJob() *fakeJob {
+	return &fakeJob{started: make(chan time.Duration, 1), release: make(chan struct{})}
+}
+
+func (job *fakeJob) Start(ctx context.Context, interval time.Duration) {
+	job.started <- interval
+	select {
+	case <-ctx.Done():
+	case <-job.release:
+	}
+}
+
+// ** Test the server runs the periodic jobs
+func TestNewJobs(t *testing.T) {
+	jobs := newJobs(nil)
+	require.Len(t, jobs, 2)
+	require.IsType(t, &interest.Job{}, jobs[0])
+	require.IsType(t, &snapshot.Job{}, jobs[1])
+}
+
+// ** Test Start Jobs runs every job until ctx is cancelled
+func TestStartJobs(t *testing.T) {
+	jobs := []*fakeJob{newFakeJob(), newFakeJob()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := startJobs(ctx, time.Minute, []periodicJob{jobs[0], jobs[1]})
+	for _, job := range jobs {
+		require.Equal(t, time.Minute, <-job.started)
+	}
+
+	cancel()
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+	defer waitCancel()
+	require.NoError(t, waitJobs(waitCtx, wg))
+}
+
+// ** Test Wait Jobs gives up once its context is done
+func TestWaitJobsTimeout(t *testing.T) {
+	job := newFakeJob()
+	defer close(job.release)
+
+	wg := startJobs(context.Background(), time.Minute, []periodicJob{job})
+	<-job.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, waitJobs(ctx, wg), context.DeadlineExceeded)
+}
@@ -0,0 +1,49 @@
+package mail
+
+import "sync"
+
+// ** FakeSender keeps sent emails in memory instead of delivering them, for tests
+type FakeSender struct {
+	mu   sync.Mutex
+	sent []Email
+
+	// ** Err, when set, is returned by SendEmail and nothing is recorded
+	Err error
+}
+
+// ** NewFakeSender creates a FakeSender that accepts every email
+func NewFakeSender() *FakeSender {
+	return &FakeSender{}
+}
+
+// ** SendEmail records the email
+func (sender *FakeSender) SendEmail(email Email) error {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	if sender.Err != nil {
+		return sender.Err
+	}
+	if _, err := buildMessage("fake@simplebank", email); err != nil {
+		return err
+	}
+	sender.sent = append(sender.sent, email)
+	return nil
+}
+
+// ** Sent returns the emails sent so far, oldest first
+func (sender *FakeSender) Sent() []Email {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	return append([]Email(nil), sender.sent...)
+}
+
+// ** Last returns the most recently sent email
+func (sender *FakeSender) Last() (Email, bool) {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	if len(sender.sent) == 0 {
+		return Email{}, false
+	}
+	return sender.sent[len(sender.sent)-1], true
+}
@@ -0,0 +1,19 @@
+package mail
+
+import "errors"
+
+// ** ErrInvalidEmail is returned for emails that cannot be sent as they are
+var ErrInvalidEmail = errors.New("invalid email")
+
+// ** Email is a plain text message to one or more recipients
+type Email struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// ** Sender is an interface for delivering emails
+type Sender interface {
+	// ** SendEmail delivers the email or returns why it could not
+	SendEmail(email Email) error
+}
@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// ** SMTPSender sends emails through an SMTP server with PLAIN authentication
+type SMTPSender struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+// ** NewSMTPSender creates a sender for the SMTP server at address (host:port).
+// ** Without a username, emails are sent unauthenticated.
+func NewSMTPSender(address, username, password, from string) (Sender, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address %q: %w", address, err)
+	}
+	if from == "" {
+		return nil, fmt.Errorf("%w: missing sender address", ErrInvalidEmail)
+	}
+
+	sender := &SMTPSender{address: address, from: from}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender, nil
+}
+
+// ** SendEmail sends the email to all its recipients in one SMTP transaction
+func (sender *SMTPSender) SendEmail(email Email) error {
+	message, err := buildMessage(sender.from, email)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(sender.address, sender.auth, sender.from, email.To, message)
+}
+
+// ** buildMessage renders the email as an RFC 5322 message with a UTF-8 plain text body
+func buildMessage(from string, email Email) ([]byte, error) {
+	if len(email.To) == 0 {
+		return nil, fmt.Errorf("%w: no recipients", ErrInvalidEmail)
+	}
+	// ** a line break in a header would let its value add headers of its own
+	for _, value := range append([]string{from, email.Subject}, email.To...) {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("%w: line break in header %q", ErrInvalidEmail, value)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(email.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", email.Subject)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes(), nil
+}
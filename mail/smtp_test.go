@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	message, err := buildMessage("bank@example.com", Email{
+		To:      []string{"alice@example.com", "bob@example.com"},
+		Subject: "Welcome",
+		Body:    "Hello,\nwelcome to Simple Bank.\n",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "From: bank@example.com\r\n"+
+		"To: alice@example.com, bob@example.com\r\n"+
+		"Subject: Welcome\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"Hello,\r\nwelcome to Simple Bank.\r\n", string(message))
+}
+
+func TestBuildMessageInvalid(t *testing.T) {
+	for _, email := range []Email{
+		{Subject: "no recipients"},
+		{To: []string{"alice@example.com"}, Subject: "Hi\r\nBcc: mallory@example.com"},
+		{To: []string{"alice@example.com\nBcc: mallory@example.com"}, Subject: "Hi"},
+	} {
+		_, err := buildMessage("bank@example.com", email)
+		require.ErrorIs(t, err, ErrInvalidEmail, email)
+	}
+}
+
+func TestNewSMTPSender(t *testing.T) {
+	_, err := NewSMTPSender("smtp.example.com:587", "user", "secret", "bank@example.com")
+	require.NoError(t, err)
+
+	_, err = NewSMTPSender("smtp.example.com", "user", "secret", "bank@example.com")
+	require.Error(t, err)
+
+	_, err = NewSMTPSender("smtp.example.com:587", "user", "secret", "")
+	require.ErrorIs(t, err, ErrInvalidEmail)
+}
+
+func TestFakeSender(t *testing.T) {
+	sender := NewFakeSender()
+	_, ok := sender.Last()
+	require.False(t, ok)
+
+	email := Email{To: []string{"alice@example.com"}, Subject: "Hi", Body: "Hello"}
+	require.NoError(t, sender.SendEmail(email))
+
+	last, ok := sender.Last()
+	require.True(t, ok)
+	require.Equal(t, email, last)
+	require.Len(t, sender.Sent(), 1)
+
+	sender.Err = errors.New("smtp down")
+	require.ErrorIs(t, sender.SendEmail(email), sender.Err)
+	require.Len(t, sender.Sent(), 1)
+}
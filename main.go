@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/techschool/simplebank/api"
+	"github.com/techschool/simplebank/db/pool"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/mail"
+	"github.com/techschool/simplebank/util"
+	"github.com/techschool/simplebank/worker"
+)
+
+// ** shutdownTimeout is how long running tasks get to finish on SIGINT or SIGTERM
+const shutdownTimeout = 30 * time.Second
+
+func main() {
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config:", err)
+	}
+
+	conn, err := pool.Open(context.Background(), config)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+
+	replica, err := pool.OpenReplica(context.Background(), config)
+	if err != nil {
+		log.Fatal("cannot connect to db:", err)
+	}
+
+	mailer, err := mail.NewSMTPSender(config.SMTPAddress, config.SMTPUsername, config.SMTPPassword, config.EmailSenderAddress)
+	if err != nil {
+		log.Fatal("cannot create email sender:", err)
+	}
+
+	store := db.NewStore(conn, db.ReadReplica(replica))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	processor := worker.NewPGTaskProcessor(store, mailer, config.PublicURL, worker.DefaultProcessorConfig)
+	err = processor.Start()
+	if err != nil {
+		log.Fatal("cannot start task processor:", err)
+	}
+
+	server, err := api.NewServer(config, store, worker.NewPGTaskDistributor(store))
+	if err != nil {
+		log.Fatal("cannot create server:", err)
+	}
+
+	go func() {
+		err := server.Start(config.ServerAddress)
+		if err != nil {
+			log.Fatal("cannot start server:", err)
+		}
+	}()
+
+	// ** the jobs stop with ctx; a run cut short is repeated on the next start
+	jobs := startJobs(ctx, jobInterval, newJobs(store))
+
+	<-ctx.Done()
+
+	log.Println("shutting down task processor")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := processor.Shutdown(shutdownCtx); err != nil {
+		log.Println("task processor did not finish in time:", err)
+	}
+	if err := waitJobs(shutdownCtx, jobs); err != nil {
+		log.Println("periodic jobs did not finish in time:", err)
+	}
+}
@@ -0,0 +1,106 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCurrency(t *testing.T) {
+	testCases := map[string]int{"EUR": 2, "USD": 2, "CAD": 2, "TRY": 2, "JPY": 0, "KWD": 3, "CLF": 4}
+	for code, exponent := range testCases {
+		currency, err := LookupCurrency(code)
+		require.NoError(t, err)
+		require.Equal(t, exponent, currency.Exponent, code)
+	}
+
+	_, err := LookupCurrency("XYZ")
+	require.ErrorIs(t, err, ErrUnknownCurrency)
+	require.False(t, IsSupportedCurrency("eur"))
+	require.NotEmpty(t, Currencies())
+}
+
+func TestParseAndFormat(t *testing.T) {
+	testCases := []struct {
+		input    string
+		amount   int64
+		currency string
+		output   string
+	}{
+		{"12.34 EUR", 1234, "EUR", "12.34 EUR"},
+		{"12.3 EUR", 1230, "EUR", "12.30 EUR"},
+		{"12 EUR", 1200, "EUR", "12.00 EUR"},
+		{"0.05 USD", 5, "USD", "0.05 USD"},
+		{"-7.5 TRY", -750, "TRY", "-7.50 TRY"},
+		{"1500 JPY", 1500, "JPY", "1500 JPY"},
+		{"1.234 KWD", 1234, "KWD", "1.234 KWD"},
+	}
+
+	for _, tc := range testCases {
+		m, err := Parse(tc.input)
+		require.NoError(t, err, tc.input)
+		require.Equal(t, Money{Amount: tc.amount, Currency: tc.currency}, m)
+		require.Equal(t, tc.output, m.String())
+	}
+
+	for _, input := range []string{"", "12.34", "12.345 EUR", "1.5 JPY", "12. EUR", ".5 EUR", "1e3 EUR", "+1 EUR", "12.34 XYZ", "99999999999999999999 EUR"} {
+		_, err := Parse(input)
+		require.Error(t, err, input)
+	}
+
+	require.Equal(t, "-92233720368547758.08 EUR", Money{Amount: math.MinInt64, Currency: "EUR"}.String())
+}
+
+func TestArithmetic(t *testing.T) {
+	a := Money{Amount: 1000, Currency: "EUR"}
+	b := Money{Amount: 250, Currency: "EUR"}
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, int64(1250), sum.Amount)
+
+	difference, err := b.Sub(a)
+	require.NoError(t, err)
+	require.Equal(t, int64(-750), difference.Amount)
+	require.True(t, difference.IsNegative())
+
+	product, err := b.Mul(3)
+	require.NoError(t, err)
+	require.Equal(t, int64(750), product.Amount)
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	_, err = a.Add(Money{Amount: 1, Currency: "USD"})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = a.Cmp(Money{Amount: 1, Currency: "USD"})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	max := Money{Amount: math.MaxInt64, Currency: "EUR"}
+	min := Money{Amount: math.MinInt64, Currency: "EUR"}
+	_, err = max.Add(Money{Amount: 1, Currency: "EUR"})
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Sub(Money{Amount: 1, Currency: "EUR"})
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Neg()
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = max.Mul(2)
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = min.Mul(-1)
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(Money{Amount: 1234, Currency: "EUR"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"12.34","currency":"EUR"}`, string(data))
+
+	var m Money
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"0.5","currency":"USD"}`), &m))
+	require.Equal(t, Money{Amount: 50, Currency: "USD"}, m)
+
+	require.Error(t, json.Unmarshal([]byte(`{"amount":"0.5","currency":"JPY"}`), &m))
+}
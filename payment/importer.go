@@ -0,0 +1,246 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** ErrAccountNotFound is returned by resolvers when no account matches an identifier
+var ErrAccountNotFound = errors.New("account not found")
+
+// ** instruction statuses and ISO 20022 reason codes used in the status report
+const (
+	StatusAccepted = "ACSC"
+	StatusRejected = "RJCT"
+
+	ReasonIncorrectAccount  = "AC01"
+	ReasonClosedAccount     = "AC04"
+	ReasonBlockedAccount    = "AC06"
+	ReasonCurrencyMismatch  = "AM03"
+	ReasonInsufficientFunds = "AM04"
+	ReasonDuplicate         = "AM05"
+	ReasonLimitExceeded     = "AM14"
+)
+
+// ** rejection is the reason reported for an instruction the store refused with err
+type rejection struct {
+	err  error
+	code string
+}
+
+// ** rejections maps the errors a transfer is refused with to the reason reported for the instruction.
+// ** Any other error is not the fault of the instruction and aborts the import.
+var rejections = []rejection{
+	{db.ErrInsufficientFunds, ReasonInsufficientFunds},
+	{db.ErrAccountClosed, ReasonClosedAccount},
+	{db.ErrAccountFrozen, ReasonBlockedAccount},
+	{db.ErrLimitExceeded, ReasonLimitExceeded},
+	{db.ErrDuplicatePayment, ReasonDuplicate},
+	{money.ErrCurrencyMismatch, ReasonCurrencyMismatch},
+	{db.ErrUnsupportedCurrency, ReasonCurrencyMismatch},
+	{db.ErrCurrencyDisabled, ReasonCurrencyMismatch},
+}
+
+// ** identifier schemes of the account identifiers registered in the store
+const (
+	SchemeIBAN  = "IBAN"
+	SchemeOther = "OTHR"
+)
+
+// ** notProvided is the end-to-end id of instructions the initiating party gave none, it identifies nothing
+const notProvided = "NOTPROVIDED"
+
+// ** AccountResolver maps the identifier used in a payment file to one of our accounts
+type AccountResolver interface {
+	ResolveAccount(ctx context.Context, id AccountIdentification) (db.Account, error)
+}
+
+// ** Transferer executes a single transfer, usually a db.Store
+type Transferer interface {
+	TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error)
+}
+
+// ** AccountGetter is the part of the store the default resolver needs
+type AccountGetter interface {
+	GetAccountByIdentifier(ctx context.Context, arg db.GetAccountByIdentifierParams) (db.Account, error)
+}
+
+// ** StoreResolver resolves IBAN and Othr/Id identifiers through the account identifiers registered in the store.
+// ** Our own account ids are never accepted as identifiers. The resolved account is paid, so identifiers
+// **  are looked up on the primary, even if a later store routes the lookup to a replica.
+type StoreResolver struct {
+	Store AccountGetter
+}
+
+func (r StoreResolver) ResolveAccount(ctx context.Context, id AccountIdentification) (db.Account, error) {
+	arg := db.GetAccountByIdentifierParams{Scheme: SchemeOther, Identifier: id.Other}
+	if id.IBAN != "" {
+		arg = db.GetAccountByIdentifierParams{Scheme: SchemeIBAN, Identifier: id.IBAN}
+	}
+	if arg.Identifier == "" {
+		return db.Account{}, ErrAccountNotFound
+	}
+
+	account, err := r.Store.GetAccountByIdentifier(db.ForcePrimary(ctx), arg)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return db.Account{}, ErrAccountNotFound
+	}
+	return account, err
+}
+
+// ** InstructionStatus is the outcome of a single credit transfer instruction
+type InstructionStatus struct {
+	PaymentInformationID string
+	InstructionID        string
+	EndToEndID           string
+	Status               string
+	ReasonCode           string
+	AdditionalInfo       string
+	Result               *db.TransferTxResult
+}
+
+// ** StatusReport is the pain.002-style report of an imported file
+type StatusReport struct {
+	OriginalMessageID    string
+	NumberOfTransactions int
+	ControlSum           string
+	Instructions         []InstructionStatus
+	CreatedAt            time.Time
+}
+
+// ** GroupStatus is ACSC when every instruction was executed, RJCT when none was, PART otherwise
+func (r StatusReport) GroupStatus() string {
+	accepted := 0
+	for _, instruction := range r.Instructions {
+		if instruction.Status == StatusAccepted {
+			accepted++
+		}
+	}
+	switch accepted {
+	case len(r.Instructions):
+		return StatusAccepted
+	case 0:
+		return StatusRejected
+	default:
+		return "PART"
+	}
+}
+
+// ** Importer executes the credit transfers of a pain.001 file one by one
+type Importer struct {
+	Resolver   AccountResolver
+	Transferer Transferer
+}
+
+// ** Import resolves the accounts of every instruction and executes it through the store.
+// ** Instructions are independent: a rejected one does not prevent the others from being executed.
+// ** The store remembers the end-to-end id of every executed instruction, so importing a file again
+// **  rejects what was already executed as a duplicate. An error that is not the fault of an instruction
+// **  aborts the import, which can then be retried as a whole.
+func (im Importer) Import(ctx context.Context, message CustomerCreditTransfer) (StatusReport, error) {
+	if err := message.Validate(); err != nil {
+		return StatusReport{}, err
+	}
+
+	report := StatusReport{
+		OriginalMessageID: message.GroupHeader.MessageID,
+		CreatedAt:         time.Now(),
+	}
+	seen := make(map[string]bool)
+	controlSum := new(big.Rat)
+
+	for _, info := range message.PaymentInformations {
+		debtor, debtorErr := im.Resolver.ResolveAccount(ctx, info.DebtorAccount)
+		if debtorErr != nil && !errors.Is(debtorErr, ErrAccountNotFound) {
+			return StatusReport{}, debtorErr
+		}
+
+		for _, transfer := range info.CreditTransfers {
+			amount, _ := transfer.Amount.Money()
+			report.NumberOfTransactions++
+			controlSum.Add(controlSum, decimal(transfer.Amount.Value))
+
+			status := InstructionStatus{
+				PaymentInformationID: info.ID,
+				InstructionID:        transfer.InstructionID,
+				EndToEndID:           transfer.EndToEndID,
+			}
+			reject := func(code, info string) {
+				status.Status = StatusRejected
+				status.ReasonCode = code
+				status.AdditionalInfo = info
+			}
+
+			// ** instructions without an end-to-end id cannot be told apart, none of them is a duplicate
+			if transfer.EndToEndID != notProvided {
+				key := info.ID + "/" + transfer.EndToEndID
+				if seen[key] {
+					reject(ReasonDuplicate, "duplicate end to end id")
+					report.Instructions = append(report.Instructions, status)
+					continue
+				}
+				seen[key] = true
+			}
+
+			if debtorErr != nil {
+				reject(ReasonIncorrectAccount, "unknown debtor account "+info.DebtorAccount.String())
+				report.Instructions = append(report.Instructions, status)
+				continue
+			}
+
+			creditor, err := im.Resolver.ResolveAccount(ctx, transfer.CreditorAccount)
+			switch {
+			case errors.Is(err, ErrAccountNotFound):
+				reject(ReasonIncorrectAccount, "unknown creditor account "+transfer.CreditorAccount.String())
+			case err != nil:
+				return StatusReport{}, err
+			case transfer.Amount.Currency != debtor.Currency || transfer.Amount.Currency != creditor.Currency:
+				reject(ReasonCurrencyMismatch, "currency "+transfer.Amount.Currency+" does not match the accounts")
+			case debtor.ID == creditor.ID:
+				reject(ReasonIncorrectAccount, "debtor and creditor accounts are the same")
+			default:
+				arg := db.TransferTxParams{
+					FromAccountID: debtor.ID,
+					ToAccountID:   creditor.ID,
+					Amount:        amount.Amount,
+					Currency:      amount.Currency,
+				}
+				if transfer.EndToEndID != notProvided {
+					arg.EndToEndID = transfer.EndToEndID
+				}
+
+				result, err := im.Transferer.TransferTx(ctx, arg)
+				if err != nil {
+					r, ok := rejectionOf(err)
+					if !ok {
+						return StatusReport{}, err
+					}
+					reject(r.code, r.err.Error())
+				} else {
+					status.Status = StatusAccepted
+					status.Result = &result
+				}
+			}
+
+			report.Instructions = append(report.Instructions, status)
+		}
+	}
+
+	report.ControlSum = formatDecimal(controlSum)
+	return report, nil
+}
+
+// ** rejectionOf finds the rejection of an error a transfer was refused with
+func rejectionOf(err error) (rejection, bool) {
+	for _, r := range rejections {
+		if errors.Is(err, r.err) {
+			return r, true
+		}
+	}
+	return rejection{}, false
+}
@@ -0,0 +1,201 @@
+package payment
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/techschool/simplebank/money"
+)
+
+// ** Pain001Namespace is the ISO 20022 CustomerCreditTransferInitiation version we accept
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// ** ErrInvalidFile is returned when a pain.001 file cannot be accepted as a whole
+var ErrInvalidFile = errors.New("invalid pain.001 file")
+
+// ** the structs below cover the parts of pain.001.001.03 we need to execute credit transfers
+type pain001Document struct {
+	XMLName          xml.Name               `xml:"Document"`
+	CstmrCdtTrfInitn CustomerCreditTransfer `xml:"CstmrCdtTrfInitn"`
+}
+
+// ** CustomerCreditTransfer is a parsed pain.001 CstmrCdtTrfInitn message
+type CustomerCreditTransfer struct {
+	GroupHeader         GroupHeader          `xml:"GrpHdr"`
+	PaymentInformations []PaymentInformation `xml:"PmtInf"`
+}
+
+type GroupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreationDateTime     string `xml:"CreDtTm"`
+	NumberOfTransactions string `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum"`
+	InitiatingPartyName  string `xml:"InitgPty>Nm"`
+}
+
+type PaymentInformation struct {
+	ID                   string                `xml:"PmtInfId"`
+	PaymentMethod        string                `xml:"PmtMtd"`
+	NumberOfTransactions string                `xml:"NbOfTxs"`
+	ControlSum           string                `xml:"CtrlSum"`
+	RequestedDate        string                `xml:"ReqdExctnDt"`
+	DebtorName           string                `xml:"Dbtr>Nm"`
+	DebtorAccount        AccountIdentification `xml:"DbtrAcct>Id"`
+	CreditTransfers      []CreditTransfer      `xml:"CdtTrfTxInf"`
+}
+
+type CreditTransfer struct {
+	InstructionID   string                `xml:"PmtId>InstrId"`
+	EndToEndID      string                `xml:"PmtId>EndToEndId"`
+	Amount          InstructedAmount      `xml:"Amt>InstdAmt"`
+	CreditorName    string                `xml:"Cdtr>Nm"`
+	CreditorAccount AccountIdentification `xml:"CdtrAcct>Id"`
+	RemittanceInfo  string                `xml:"RmtInf>Ustrd"`
+}
+
+type InstructedAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// ** AccountIdentification identifies an account either by IBAN or by another scheme (Othr/Id)
+type AccountIdentification struct {
+	IBAN  string `xml:"IBAN"`
+	Other string `xml:"Othr>Id"`
+}
+
+func (id AccountIdentification) String() string {
+	if id.IBAN != "" {
+		return id.IBAN
+	}
+	return id.Other
+}
+
+// ** ParsePain001 decodes and validates a pain.001 file
+func ParsePain001(r io.Reader) (CustomerCreditTransfer, error) {
+	var doc pain001Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return CustomerCreditTransfer{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if doc.XMLName.Space != Pain001Namespace {
+		return CustomerCreditTransfer{}, fmt.Errorf("%w: unexpected namespace %q", ErrInvalidFile, doc.XMLName.Space)
+	}
+
+	message := doc.CstmrCdtTrfInitn
+	if err := message.Validate(); err != nil {
+		return CustomerCreditTransfer{}, err
+	}
+	return message, nil
+}
+
+// ** Validate checks the group header and payment information blocks against their transactions
+func (m CustomerCreditTransfer) Validate() error {
+	header := m.GroupHeader
+	if header.MessageID == "" {
+		return fmt.Errorf("%w: missing GrpHdr/MsgId", ErrInvalidFile)
+	}
+	if len(m.PaymentInformations) == 0 {
+		return fmt.Errorf("%w: no PmtInf blocks", ErrInvalidFile)
+	}
+
+	var count int
+	sum := new(big.Rat)
+	for _, info := range m.PaymentInformations {
+		if info.ID == "" {
+			return fmt.Errorf("%w: missing PmtInfId", ErrInvalidFile)
+		}
+		if info.PaymentMethod != "TRF" {
+			return fmt.Errorf("%w: PmtInf %s has unsupported payment method %q", ErrInvalidFile, info.ID, info.PaymentMethod)
+		}
+		if info.DebtorAccount.String() == "" {
+			return fmt.Errorf("%w: PmtInf %s has no debtor account", ErrInvalidFile, info.ID)
+		}
+		if len(info.CreditTransfers) == 0 {
+			return fmt.Errorf("%w: PmtInf %s has no credit transfers", ErrInvalidFile, info.ID)
+		}
+
+		infoSum := new(big.Rat)
+		for _, transfer := range info.CreditTransfers {
+			if transfer.EndToEndID == "" {
+				return fmt.Errorf("%w: PmtInf %s has a transaction without EndToEndId", ErrInvalidFile, info.ID)
+			}
+			if transfer.CreditorAccount.String() == "" {
+				return fmt.Errorf("%w: transaction %s has no creditor account", ErrInvalidFile, transfer.EndToEndID)
+			}
+			if _, err := transfer.Amount.Money(); err != nil {
+				return fmt.Errorf("%w: transaction %s: %v", ErrInvalidFile, transfer.EndToEndID, err)
+			}
+			infoSum.Add(infoSum, decimal(transfer.Amount.Value))
+		}
+
+		if err := checkTotals(info.NumberOfTransactions, info.ControlSum, len(info.CreditTransfers), infoSum); err != nil {
+			return fmt.Errorf("%w: PmtInf %s: %v", ErrInvalidFile, info.ID, err)
+		}
+		count += len(info.CreditTransfers)
+		sum.Add(sum, infoSum)
+	}
+
+	if header.NumberOfTransactions == "" {
+		return fmt.Errorf("%w: missing GrpHdr/NbOfTxs", ErrInvalidFile)
+	}
+	if err := checkTotals(header.NumberOfTransactions, header.ControlSum, count, sum); err != nil {
+		return fmt.Errorf("%w: GrpHdr: %v", ErrInvalidFile, err)
+	}
+	return nil
+}
+
+// ** checkTotals compares the optional NbOfTxs and CtrlSum fields with the actual transactions.
+// ** CtrlSum adds up plain decimals across currencies, so it is compared as an exact rational.
+func checkTotals(numberOfTransactions, controlSum string, count int, sum *big.Rat) error {
+	if numberOfTransactions != "" {
+		n, err := strconv.Atoi(numberOfTransactions)
+		if err != nil {
+			return fmt.Errorf("invalid NbOfTxs %q", numberOfTransactions)
+		}
+		if n != count {
+			return fmt.Errorf("NbOfTxs is %d but found %d transactions", n, count)
+		}
+	}
+	if controlSum != "" {
+		expected, ok := new(big.Rat).SetString(strings.TrimSpace(controlSum))
+		if !ok {
+			return fmt.Errorf("invalid CtrlSum %q", controlSum)
+		}
+		if expected.Cmp(sum) != 0 {
+			return fmt.Errorf("CtrlSum is %s but transactions add up to %s", controlSum, formatDecimal(sum))
+		}
+	}
+	return nil
+}
+
+// ** Money converts the instructed amount into minor units of its currency; it must be positive
+func (a InstructedAmount) Money() (money.Money, error) {
+	amount, err := money.ParseDecimal(strings.TrimSpace(a.Value), a.Currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if !amount.IsPositive() {
+		return money.Money{}, fmt.Errorf("amount %s must be positive", amount)
+	}
+	return amount, nil
+}
+
+// ** decimal parses an amount that has already been validated
+func decimal(value string) *big.Rat {
+	r, _ := new(big.Rat).SetString(strings.TrimSpace(value))
+	return r
+}
+
+// ** formatDecimal renders a sum of amounts with as few decimals as needed, but at least two
+func formatDecimal(r *big.Rat) string {
+	s := r.FloatString(4)
+	for strings.HasSuffix(s, "0") && len(s)-strings.Index(s, ".") > 3 {
+		s = s[:len(s)-1]
+	}
+	return s
+}
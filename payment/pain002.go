@@ -0,0 +1,116 @@
+package payment
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// ** Pain002Namespace is the ISO 20022 CustomerPaymentStatusReport version we produce
+const Pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+const isoDateTime = "2006-01-02T15:04:05Z07:00"
+
+// ** the structs below follow the element order of pain.002.001.03
+type pain002Document struct {
+	XMLName        xml.Name      `xml:"urn:iso:std:iso:20022:tech:xsd:pain.002.001.03 Document"`
+	CstmrPmtStsRpt pain002Report `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002Report struct {
+	GrpHdr            pain002GroupHeader      `xml:"GrpHdr"`
+	OrgnlGrpInfAndSts pain002OriginalGroup    `xml:"OrgnlGrpInfAndSts"`
+	OrgnlPmtInfAndSts []pain002OriginalPmtInf `xml:"OrgnlPmtInfAndSts"`
+}
+
+type pain002GroupHeader struct {
+	MsgId   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type pain002OriginalGroup struct {
+	OrgnlMsgId   string `xml:"OrgnlMsgId"`
+	OrgnlMsgNmId string `xml:"OrgnlMsgNmId"`
+	OrgnlNbOfTxs string `xml:"OrgnlNbOfTxs"`
+	OrgnlCtrlSum string `xml:"OrgnlCtrlSum"`
+	GrpSts       string `xml:"GrpSts"`
+}
+
+type pain002OriginalPmtInf struct {
+	OrgnlPmtInfId string            `xml:"OrgnlPmtInfId"`
+	TxInfAndSts   []pain002TxStatus `xml:"TxInfAndSts"`
+}
+
+type pain002TxStatus struct {
+	StsId           string         `xml:"StsId"`
+	OrgnlInstrId    string         `xml:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId string         `xml:"OrgnlEndToEndId"`
+	TxSts           string         `xml:"TxSts"`
+	StsRsnInf       *pain002Reason `xml:"StsRsnInf,omitempty"`
+	AcctSvcrRef     string         `xml:"AcctSvcrRef,omitempty"`
+}
+
+type pain002Reason struct {
+	Cd       string `xml:"Rsn>Cd"`
+	AddtlInf string `xml:"AddtlInf,omitempty"`
+}
+
+// ** WritePain002 writes the status report as a pain.002.001.03 document
+func WritePain002(w io.Writer, report StatusReport) error {
+	doc := pain002Document{CstmrPmtStsRpt: pain002Report{
+		GrpHdr: pain002GroupHeader{
+			MsgId:   "STS-" + report.OriginalMessageID,
+			CreDtTm: report.CreatedAt.UTC().Format(isoDateTime),
+		},
+		OrgnlGrpInfAndSts: pain002OriginalGroup{
+			OrgnlMsgId:   report.OriginalMessageID,
+			OrgnlMsgNmId: "pain.001.001.03",
+			OrgnlNbOfTxs: strconv.Itoa(report.NumberOfTransactions),
+			OrgnlCtrlSum: report.ControlSum,
+			GrpSts:       report.GroupStatus(),
+		},
+	}}
+
+	blocks := doc.CstmrPmtStsRpt.OrgnlPmtInfAndSts
+	for i, instruction := range report.Instructions {
+		if len(blocks) == 0 || blocks[len(blocks)-1].OrgnlPmtInfId != instruction.PaymentInformationID {
+			blocks = append(blocks, pain002OriginalPmtInf{OrgnlPmtInfId: instruction.PaymentInformationID})
+		}
+
+		status := pain002TxStatus{
+			StsId:           strconv.Itoa(i + 1),
+			OrgnlInstrId:    instruction.InstructionID,
+			OrgnlEndToEndId: instruction.EndToEndID,
+			TxSts:           instruction.Status,
+		}
+		if instruction.ReasonCode != "" {
+			status.StsRsnInf = &pain002Reason{Cd: instruction.ReasonCode, AddtlInf: truncate(instruction.AdditionalInfo, 105)}
+		}
+		if instruction.Result != nil {
+			status.AcctSvcrRef = strconv.FormatInt(instruction.Result.Transfer.ID, 10)
+		}
+
+		last := &blocks[len(blocks)-1]
+		last.TxInfAndSts = append(last.TxInfAndSts, status)
+	}
+	doc.CstmrPmtStsRpt.OrgnlPmtInfAndSts = blocks
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ** truncate keeps free text within the length allowed by the schema
+func truncate(text string, max int) string {
+	if len(text) > max {
+		return text[:max]
+	}
+	return text
+}
@@ -0,0 +1,297 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// ** fakeStore keeps accounts in memory and records executed transfers
+type fakeStore struct {
+	accounts    map[int64]db.Account
+	identifiers map[db.GetAccountByIdentifierParams]int64
+	transfers   []db.TransferTxParams
+	// ** when set, TransferTx fails with it
+	err error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		accounts: map[int64]db.Account{
+			1: {ID: 1, Owner: "acme", Balance: 100000, Currency: "EUR"},
+			2: {ID: 2, Owner: "supplier", Balance: 0, Currency: "EUR"},
+			3: {ID: 3, Owner: "dollar", Balance: 0, Currency: "USD"},
+			4: {ID: 4, Owner: "unregistered", Balance: 0, Currency: "EUR"},
+		},
+		identifiers: map[db.GetAccountByIdentifierParams]int64{
+			{Scheme: SchemeOther, Identifier: "1"}:                     1,
+			{Scheme: SchemeOther, Identifier: "2"}:                     2,
+			{Scheme: SchemeOther, Identifier: "3"}:                     3,
+			{Scheme: SchemeIBAN, Identifier: "DE89370400440532013000"}: 2,
+		},
+	}
+}
+
+func (f *fakeStore) GetAccountByIdentifier(ctx context.Context, arg db.GetAccountByIdentifierParams) (db.Account, error) {
+	if !db.IsPrimaryForced(ctx) {
+		return db.Account{}, errors.New("identifier looked up on the replica")
+	}
+	account, ok := f.accounts[f.identifiers[arg]]
+	if !ok {
+		return db.Account{}, db.ErrRecordNotFound
+	}
+	return account, nil
+}
+
+func (f *fakeStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	if f.err != nil {
+		return db.TransferTxResult{}, f.err
+	}
+	for _, transfer := range f.transfers {
+		if arg.EndToEndID != "" && transfer.FromAccountID == arg.FromAccountID && transfer.EndToEndID == arg.EndToEndID {
+			return db.TransferTxResult{}, db.ErrDuplicatePayment
+		}
+	}
+	f.transfers = append(f.transfers, arg)
+	return db.TransferTxResult{Transfer: db.Transfer{
+		ID:            int64(len(f.transfers)),
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+	}}, nil
+}
+
+func parseTestFile(t *testing.T) CustomerCreditTransfer {
+	file, err := os.Open(filepath.Join("testdata", "pain001.xml"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	message, err := ParsePain001(file)
+	require.NoError(t, err)
+	return message
+}
+
+func TestParsePain001(t *testing.T) {
+	message := parseTestFile(t)
+
+	require.Equal(t, "BATCH-0001", message.GroupHeader.MessageID)
+	require.Len(t, message.PaymentInformations, 1)
+
+	info := message.PaymentInformations[0]
+	require.Equal(t, "1", info.DebtorAccount.Other)
+	require.Len(t, info.CreditTransfers, 4)
+	require.Equal(t, "E2E-1", info.CreditTransfers[0].EndToEndID)
+	require.Equal(t, "EUR", info.CreditTransfers[0].Amount.Currency)
+	require.Equal(t, "Invoice 42", info.CreditTransfers[0].RemittanceInfo)
+}
+
+func TestParsePain001Invalid(t *testing.T) {
+	valid, err := os.ReadFile(filepath.Join("testdata", "pain001.xml"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		replace [2]string
+	}{
+		{"wrong namespace", [2]string{"pain.001.001.03", "pain.001.001.09"}},
+		{"wrong control sum", [2]string{"<CtrlSum>186.50</CtrlSum>\n      <InitgPty>", "<CtrlSum>186.00</CtrlSum>\n      <InitgPty>"}},
+		{"wrong transaction count", [2]string{"<NbOfTxs>4</NbOfTxs>\n      <CtrlSum>186.50</CtrlSum>\n      <ReqdExctnDt>", "<NbOfTxs>3</NbOfTxs>\n      <CtrlSum>186.50</CtrlSum>\n      <ReqdExctnDt>"}},
+		{"unsupported payment method", [2]string{"<PmtMtd>TRF</PmtMtd>", "<PmtMtd>CHK</PmtMtd>"}},
+		{"invalid amount", [2]string{">35.00<", ">35.001<"}},
+		{"negative amount", [2]string{">35.00<", ">-35.00<"}},
+		{"malformed xml", [2]string{"</Document>", ""}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := strings.Replace(string(valid), tc.replace[0], tc.replace[1], 1)
+			require.NotEqual(t, string(valid), content)
+
+			_, err := ParsePain001(strings.NewReader(content))
+			require.ErrorIs(t, err, ErrInvalidFile)
+		})
+	}
+}
+
+func TestInstructedAmountMoney(t *testing.T) {
+	amount, err := InstructedAmount{Currency: "EUR", Value: "50.5"}.Money()
+	require.NoError(t, err)
+	require.Equal(t, money.Money{Amount: 5050, Currency: "EUR"}, amount)
+
+	amount, err = InstructedAmount{Currency: "JPY", Value: "7"}.Money()
+	require.NoError(t, err)
+	require.Equal(t, money.Money{Amount: 7, Currency: "JPY"}, amount)
+
+	for _, value := range []InstructedAmount{
+		{Currency: "EUR", Value: ""},
+		{Currency: "EUR", Value: "0"},
+		{Currency: "EUR", Value: "1.234"},
+		{Currency: "EUR", Value: "-1"},
+		{Currency: "EUR", Value: "1.2.3"},
+		{Currency: "EUR", Value: "abc"},
+		{Currency: "JPY", Value: "1.5"},
+		{Currency: "XXY", Value: "1"},
+	} {
+		_, err = value.Money()
+		require.Error(t, err, value)
+	}
+}
+
+func TestImport(t *testing.T) {
+	store := newFakeStore()
+	importer := Importer{Resolver: StoreResolver{Store: store}, Transferer: store}
+
+	report, err := importer.Import(context.Background(), parseTestFile(t))
+	require.NoError(t, err)
+	report.CreatedAt = time.Date(2023, time.March, 1, 8, 5, 0, 0, time.UTC)
+
+	// ** only the first instruction can be executed
+	require.Equal(t, []db.TransferTxParams{{FromAccountID: 1, ToAccountID: 2, Amount: 10000, Currency: "EUR", EndToEndID: "E2E-1"}}, store.transfers)
+	require.Equal(t, "PART", report.GroupStatus())
+	require.Equal(t, 4, report.NumberOfTransactions)
+	require.Equal(t, "186.50", report.ControlSum)
+
+	require.Len(t, report.Instructions, 4)
+	require.Equal(t, StatusAccepted, report.Instructions[0].Status)
+	require.Equal(t, ReasonIncorrectAccount, report.Instructions[1].ReasonCode)
+	require.Equal(t, ReasonCurrencyMismatch, report.Instructions[2].ReasonCode)
+	require.Equal(t, ReasonDuplicate, report.Instructions[3].ReasonCode)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePain002(&buf, report))
+	checkGolden(t, "pain002.golden.xml", buf.Bytes())
+}
+
+func TestImportTwice(t *testing.T) {
+	store := newFakeStore()
+	importer := Importer{Resolver: StoreResolver{Store: store}, Transferer: store}
+
+	_, err := importer.Import(context.Background(), parseTestFile(t))
+	require.NoError(t, err)
+	require.Len(t, store.transfers, 1)
+
+	// ** the instruction executed by the first import is a duplicate the second time
+	report, err := importer.Import(context.Background(), parseTestFile(t))
+	require.NoError(t, err)
+	require.Len(t, store.transfers, 1)
+	require.Equal(t, StatusRejected, report.GroupStatus())
+	require.Equal(t, ReasonDuplicate, report.Instructions[0].ReasonCode)
+}
+
+func TestImportWithoutEndToEndID(t *testing.T) {
+	store := newFakeStore()
+	importer := Importer{Resolver: StoreResolver{Store: store}, Transferer: store}
+
+	transfer := CreditTransfer{
+		EndToEndID:      notProvided,
+		Amount:          InstructedAmount{Currency: "EUR", Value: "10.00"},
+		CreditorAccount: AccountIdentification{Other: "2"},
+	}
+	message := CustomerCreditTransfer{
+		GroupHeader: GroupHeader{MessageID: "MSG-1", NumberOfTransactions: "2"},
+		PaymentInformations: []PaymentInformation{{
+			ID:              "PMT-1",
+			PaymentMethod:   "TRF",
+			DebtorAccount:   AccountIdentification{Other: "1"},
+			CreditTransfers: []CreditTransfer{transfer, transfer},
+		}},
+	}
+
+	// ** both instructions are executed, and neither records NOTPROVIDED as its end-to-end id
+	report, err := importer.Import(context.Background(), message)
+	require.NoError(t, err)
+	require.Equal(t, StatusAccepted, report.GroupStatus())
+	require.Len(t, report.Instructions, 2)
+	for _, instruction := range report.Instructions {
+		require.Equal(t, StatusAccepted, instruction.Status)
+	}
+	require.Len(t, store.transfers, 2)
+	for _, transfer := range store.transfers {
+		require.Empty(t, transfer.EndToEndID)
+	}
+}
+
+func TestImportRejectionReasons(t *testing.T) {
+	testCases := []struct {
+		err    error
+		reason string
+	}{
+		{fmt.Errorf("%w: account 1 has 0 available, 10000 needed", db.ErrInsufficientFunds), ReasonInsufficientFunds},
+		{db.ErrAccountClosed, ReasonClosedAccount},
+		{db.ErrAccountFrozen, ReasonBlockedAccount},
+		{db.ErrLimitExceeded, ReasonLimitExceeded},
+		{db.ErrCurrencyDisabled, ReasonCurrencyMismatch},
+		{money.ErrCurrencyMismatch, ReasonCurrencyMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.reason, func(t *testing.T) {
+			store := newFakeStore()
+			store.err = tc.err
+			importer := Importer{Resolver: StoreResolver{Store: store}, Transferer: store}
+
+			report, err := importer.Import(context.Background(), parseTestFile(t))
+			require.NoError(t, err)
+			require.Equal(t, StatusRejected, report.Instructions[0].Status)
+			require.Equal(t, tc.reason, report.Instructions[0].ReasonCode)
+			// ** the details of the refusal stay in the bank
+			require.NotContains(t, report.Instructions[0].AdditionalInfo, "available")
+		})
+	}
+}
+
+func TestImportInfrastructureError(t *testing.T) {
+	store := newFakeStore()
+	store.err = errors.New("connection reset by peer")
+	importer := Importer{Resolver: StoreResolver{Store: store}, Transferer: store}
+
+	_, err := importer.Import(context.Background(), parseTestFile(t))
+	require.ErrorIs(t, err, store.err)
+}
+
+func TestStoreResolver(t *testing.T) {
+	resolver := StoreResolver{Store: newFakeStore()}
+	ctx := context.Background()
+
+	// ** the fake only answers lookups forced to the primary
+	_, err := resolver.Store.GetAccountByIdentifier(ctx, db.GetAccountByIdentifierParams{Scheme: SchemeOther, Identifier: "1"})
+	require.Error(t, err)
+
+	account, err := resolver.ResolveAccount(ctx, AccountIdentification{IBAN: "DE89370400440532013000"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), account.ID)
+
+	account, err = resolver.ResolveAccount(ctx, AccountIdentification{Other: "3"})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), account.ID)
+
+	// ** an account without a registered identifier cannot be addressed by its id
+	for _, id := range []AccountIdentification{{Other: "4"}, {IBAN: "DE02120300000000202051"}, {}} {
+		_, err = resolver.ResolveAccount(ctx, id)
+		require.ErrorIs(t, err, ErrAccountNotFound)
+	}
+}
+
+func checkGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
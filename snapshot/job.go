@@ -0,0 +1,70 @@
+package snapshot
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ** Store is the part of db.Store the snapshot job needs
+type Store interface {
+	CreateBalanceSnapshots(ctx context.Context, snapshotAt time.Time) (int64, error)
+}
+
+// ** Job takes a balance snapshot of every account at midnight UTC, so historical
+// **  balances only need the entries of a single day on top of a snapshot.
+// ** Snapshots are idempotent: running the job twice for the same day has no further effect.
+// ** An entry is stamped when its transaction starts but only seen once it commits, so a
+// **  snapshot taken right after midnight could miss an entry of the day before that is still
+// **  in flight, and GetBalanceAt would never count it. The job therefore only snapshots a
+// **  midnight once settleTime has passed, far longer than any store transaction runs.
+// ** Nothing in the database enforces that bound: a transaction held open longer than
+// **  settleTime across midnight can still be missed.
+type Job struct {
+	store Store
+}
+
+// ** NewJob creates a new snapshot job
+func NewJob(store Store) *Job {
+	return &Job{store: store}
+}
+
+// ** settleTime is how long after midnight the job waits before snapshotting it
+const settleTime = time.Hour
+
+// ** startOfDay truncates a time to midnight UTC
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ** Run snapshots the balances at the latest midnight at least settleTime ago
+// **  and returns the number of new snapshots
+func (job *Job) Run(ctx context.Context, now time.Time) (int64, error) {
+	day := startOfDay(now.Add(-settleTime))
+
+	n, err := job.store.CreateBalanceSnapshots(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("snapshot: %d balance snapshots at %s", n, day.Format("2006-01-02"))
+	return n, nil
+}
+
+// ** Start runs the job once and then on every tick of interval until ctx is cancelled
+func (job *Job) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := job.Run(ctx, time.Now()); err != nil {
+			log.Printf("snapshot: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
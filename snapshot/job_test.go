@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ** fakeStore records snapshot times with the same uniqueness rule as the database
+type fakeStore struct {
+	accounts  int64
+	snapshots map[time.Time]bool
+}
+
+func (f *fakeStore) CreateBalanceSnapshots(ctx context.Context, snapshotAt time.Time) (int64, error) {
+	if f.snapshots[snapshotAt] {
+		return 0, nil
+	}
+	f.snapshots[snapshotAt] = true
+	return f.accounts, nil
+}
+
+func TestRunSnapshotsAtMidnight(t *testing.T) {
+	store := &fakeStore{accounts: 3, snapshots: make(map[time.Time]bool)}
+	job := NewJob(store)
+
+	paris := time.FixedZone("CET", 3600)
+	n, err := job.Run(context.Background(), time.Date(2023, time.March, 2, 0, 30, 0, 0, paris))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	require.True(t, store.snapshots[time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)])
+
+	// ** running again the same day adds nothing
+	n, err = job.Run(context.Background(), time.Date(2023, time.March, 1, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.Len(t, store.snapshots, 1)
+}
+
+func TestRunWaitsForMidnightToSettle(t *testing.T) {
+	store := &fakeStore{accounts: 3, snapshots: make(map[time.Time]bool)}
+	job := NewJob(store)
+
+	// ** transactions started before midnight may still commit, so the day before is snapshot
+	_, err := job.Run(context.Background(), time.Date(2023, time.March, 2, 0, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, map[time.Time]bool{time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC): true}, store.snapshots)
+
+	_, err = job.Run(context.Background(), time.Date(2023, time.March, 2, 1, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, store.snapshots[time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC)])
+}
@@ -0,0 +1,289 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ** Camt053Namespace is the ISO 20022 BankToCustomerStatement version we produce
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+const (
+	isoDateTime = "2006-01-02T15:04:05Z07:00"
+	isoDate     = "2006-01-02"
+)
+
+// ** the structs below follow the element order of camt.053.001.02, which the schema enforces
+type camtDocument struct {
+	XMLName       xml.Name          `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	BkToCstmrStmt camtBkToCstmrStmt `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstmrStmt struct {
+	GrpHdr camtGroupHeader `xml:"GrpHdr"`
+	Stmt   camtStatement   `xml:"Stmt"`
+}
+
+type camtGroupHeader struct {
+	MsgId   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	Id           string               `xml:"Id"`
+	ElctrncSeqNb int64                `xml:"ElctrncSeqNb"`
+	CreDtTm      string               `xml:"CreDtTm"`
+	FrToDt       camtDateTimePeriod   `xml:"FrToDt"`
+	Acct         camtAccount          `xml:"Acct"`
+	Bal          []camtBalance        `xml:"Bal"`
+	TxsSummry    camtTotalTransaction `xml:"TxsSummry"`
+	Ntry         []camtEntry          `xml:"Ntry"`
+}
+
+type camtDateTimePeriod struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	Id   camtAccountId `xml:"Id"`
+	Ccy  string        `xml:"Ccy,omitempty"`
+	Ownr *camtParty    `xml:"Ownr,omitempty"`
+}
+
+type camtAccountId struct {
+	Othr camtGenericId `xml:"Othr"`
+}
+
+type camtGenericId struct {
+	Id string `xml:"Id"`
+}
+
+type camtParty struct {
+	Nm string `xml:"Nm"`
+}
+
+type camtBalance struct {
+	Tp        camtBalanceType `xml:"Tp"`
+	Amt       camtAmount      `xml:"Amt"`
+	CdtDbtInd string          `xml:"CdtDbtInd"`
+	Dt        camtDate        `xml:"Dt"`
+}
+
+type camtBalanceType struct {
+	CdOrPrtry camtCode `xml:"CdOrPrtry"`
+}
+
+type camtCode struct {
+	Cd string `xml:"Cd"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Dt string `xml:"Dt"`
+}
+
+type camtTotalTransaction struct {
+	TtlNtries    camtNetTotal `xml:"TtlNtries"`
+	TtlCdtNtries camtTotal    `xml:"TtlCdtNtries"`
+	TtlDbtNtries camtTotal    `xml:"TtlDbtNtries"`
+}
+
+type camtNetTotal struct {
+	NbOfNtries    string `xml:"NbOfNtries"`
+	Sum           string `xml:"Sum"`
+	TtlNetNtryAmt string `xml:"TtlNetNtryAmt"`
+	CdtDbtInd     string `xml:"CdtDbtInd"`
+}
+
+type camtTotal struct {
+	NbOfNtries string `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	NtryRef     string         `xml:"NtryRef"`
+	Amt         camtAmount     `xml:"Amt"`
+	CdtDbtInd   string         `xml:"CdtDbtInd"`
+	Sts         string         `xml:"Sts"`
+	BookgDt     camtDate       `xml:"BookgDt"`
+	ValDt       camtDate       `xml:"ValDt"`
+	AcctSvcrRef string         `xml:"AcctSvcrRef"`
+	BkTxCd      camtBankTxCode `xml:"BkTxCd"`
+	NtryDtls    *camtEntryDtls `xml:"NtryDtls,omitempty"`
+}
+
+type camtBankTxCode struct {
+	Domn camtDomain `xml:"Domn"`
+}
+
+type camtDomain struct {
+	Cd   string     `xml:"Cd"`
+	Fmly camtFamily `xml:"Fmly"`
+}
+
+type camtFamily struct {
+	Cd        string `xml:"Cd"`
+	SubFmlyCd string `xml:"SubFmlyCd"`
+}
+
+type camtEntryDtls struct {
+	TxDtls camtTxDtls `xml:"TxDtls"`
+}
+
+type camtTxDtls struct {
+	Refs      camtRefs          `xml:"Refs"`
+	RltdPties *camtRelatedParty `xml:"RltdPties,omitempty"`
+}
+
+type camtRefs struct {
+	AcctSvcrRef string `xml:"AcctSvcrRef"`
+	EndToEndId  string `xml:"EndToEndId"`
+	TxId        string `xml:"TxId"`
+}
+
+type camtRelatedParty struct {
+	DbtrAcct *camtRelatedAccount `xml:"DbtrAcct,omitempty"`
+	CdtrAcct *camtRelatedAccount `xml:"CdtrAcct,omitempty"`
+}
+
+type camtRelatedAccount struct {
+	Id camtAccountId `xml:"Id"`
+}
+
+// ** WriteCamt053 writes the statement as an ISO 20022 camt.053.001.02 document
+func WriteCamt053(w io.Writer, s Statement) error {
+	currency := s.Account.Currency
+	accountID := strconv.FormatInt(s.Account.ID, 10)
+	statementID := fmt.Sprintf("%d-%s-%d", s.Account.ID, s.From.UTC().Format("20060102"), s.Number)
+
+	creditCount, creditTotal := s.Credits()
+	debitCount, debitTotal := s.Debits()
+	net := creditTotal - debitTotal
+
+	stmt := camtStatement{
+		Id:           statementID,
+		ElctrncSeqNb: s.Number,
+		CreDtTm:      s.CreatedAt.UTC().Format(isoDateTime),
+		FrToDt: camtDateTimePeriod{
+			FrDtTm: s.From.UTC().Format(isoDateTime),
+			ToDtTm: s.lastDay().UTC().Format(isoDateTime),
+		},
+		Acct: camtAccount{
+			Id:  camtAccountId{Othr: camtGenericId{Id: accountID}},
+			Ccy: currency,
+		},
+		Bal: []camtBalance{
+			camtBal("OPBD", s.OpeningBalance, currency, s.From),
+			camtBal("CLBD", s.ClosingBalance, currency, s.lastDay()),
+		},
+		TxsSummry: camtTotalTransaction{
+			TtlNtries: camtNetTotal{
+				NbOfNtries:    strconv.Itoa(len(s.Lines)),
+				Sum:           formatAmount(creditTotal+debitTotal, currency, "."),
+				TtlNetNtryAmt: formatAmount(net, currency, "."),
+				CdtDbtInd:     creditDebit(net),
+			},
+			TtlCdtNtries: camtTotal{NbOfNtries: strconv.Itoa(creditCount), Sum: formatAmount(creditTotal, currency, ".")},
+			TtlDbtNtries: camtTotal{NbOfNtries: strconv.Itoa(debitCount), Sum: formatAmount(debitTotal, currency, ".")},
+		},
+	}
+	if s.Account.Owner != "" {
+		stmt.Acct.Ownr = &camtParty{Nm: s.Account.Owner}
+	}
+
+	for _, line := range s.Lines {
+		entryRef := strconv.FormatInt(line.EntryID, 10)
+		ntry := camtEntry{
+			NtryRef:     entryRef,
+			Amt:         camtAmount{Ccy: currency, Value: formatAmount(line.Amount, currency, ".")},
+			CdtDbtInd:   creditDebit(line.Amount),
+			Sts:         "BOOK",
+			BookgDt:     camtDate{Dt: line.BookedAt.UTC().Format(isoDate)},
+			ValDt:       camtDate{Dt: line.BookedAt.UTC().Format(isoDate)},
+			AcctSvcrRef: entryRef,
+			BkTxCd:      camtBankTxCode{Domn: camtDomain{Cd: "PMNT", Fmly: camtTxFamily(line)}},
+		}
+
+		if line.TransferID != 0 {
+			transferRef := strconv.FormatInt(line.TransferID, 10)
+			details := &camtEntryDtls{TxDtls: camtTxDtls{
+				Refs: camtRefs{AcctSvcrRef: entryRef, EndToEndId: transferRef, TxId: transferRef},
+			}}
+			if line.CounterpartyAccountID != 0 {
+				counterparty := &camtRelatedAccount{
+					Id: camtAccountId{Othr: camtGenericId{Id: strconv.FormatInt(line.CounterpartyAccountID, 10)}},
+				}
+				if line.Amount < 0 {
+					details.TxDtls.RltdPties = &camtRelatedParty{CdtrAcct: counterparty}
+				} else {
+					details.TxDtls.RltdPties = &camtRelatedParty{DbtrAcct: counterparty}
+				}
+			}
+			ntry.NtryDtls = details
+		}
+
+		stmt.Ntry = append(stmt.Ntry, ntry)
+	}
+
+	doc := camtDocument{BkToCstmrStmt: camtBkToCstmrStmt{
+		GrpHdr: camtGroupHeader{
+			MsgId:   "CAMT053-" + statementID,
+			CreDtTm: s.CreatedAt.UTC().Format(isoDateTime),
+		},
+		Stmt: stmt,
+	}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func camtBal(code string, amount int64, currency string, date time.Time) camtBalance {
+	return camtBalance{
+		Tp:        camtBalanceType{CdOrPrtry: camtCode{Cd: code}},
+		Amt:       camtAmount{Ccy: currency, Value: formatAmount(amount, currency, ".")},
+		CdtDbtInd: creditDebit(amount),
+		Dt:        camtDate{Dt: date.UTC().Format(isoDate)},
+	}
+}
+
+// ** camtTxFamily maps a line to the ISO bank transaction code family:
+// **  internal book transfers for transfers, charges for their fees, miscellaneous operations otherwise
+func camtTxFamily(line Line) camtFamily {
+	switch {
+	case line.Fee && line.Amount < 0:
+		return camtFamily{Cd: "MDOP", SubFmlyCd: "CHRG"}
+	case line.Fee:
+		return camtFamily{Cd: "MCOP", SubFmlyCd: "CHRG"}
+	case line.TransferID != 0 && line.Amount < 0:
+		return camtFamily{Cd: "ICDT", SubFmlyCd: "BOOK"}
+	case line.TransferID != 0:
+		return camtFamily{Cd: "RCDT", SubFmlyCd: "BOOK"}
+	case line.Amount < 0:
+		return camtFamily{Cd: "MDOP", SubFmlyCd: "OTHR"}
+	default:
+		return camtFamily{Cd: "MCOP", SubFmlyCd: "OTHR"}
+	}
+}
+
+func creditDebit(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
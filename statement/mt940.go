@@ -0,0 +1,86 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ** SWIFT MT940 fields are limited in length, references to 16 characters
+const mt940ReferenceLength = 16
+
+// ** WriteMT940 writes the statement as a SWIFT MT940 customer statement message (block 4 only)
+func WriteMT940(w io.Writer, s Statement) error {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	currency := s.Account.Currency
+	add(":20:%s", mt940Reference(fmt.Sprintf("%d-%s", s.Account.ID, s.From.UTC().Format("060102"))))
+	add(":25:%d", s.Account.ID)
+	add(":28C:%05d/1", s.Number)
+	add(":60F:%s", mt940Balance(s.OpeningBalance, s.From, currency))
+
+	for _, line := range s.Lines {
+		booked := line.BookedAt.UTC()
+		reference := "NONREF"
+		if line.TransferID != 0 {
+			reference = mt940Reference("TRF" + strconv.FormatInt(line.TransferID, 10))
+		}
+		// ** N followed by the SWIFT transaction type: transfer, or charges for a fee
+		transactionType := "NTRF"
+		if line.Fee {
+			transactionType = "NCHG"
+		}
+		add(":61:%s%s%s%s%s%s//%s",
+			booked.Format("060102"),
+			booked.Format("0102"),
+			mt940CreditDebit(line.Amount),
+			formatAmount(line.Amount, currency, ","),
+			transactionType,
+			reference,
+			mt940Reference("E"+strconv.FormatInt(line.EntryID, 10)),
+		)
+
+		switch {
+		case line.Fee && line.CounterpartyAccountID != 0:
+			add(":86:FEE OF TRANSFER %d FROM ACCOUNT %d", line.TransferID, line.CounterpartyAccountID)
+		case line.Fee:
+			add(":86:FEE OF TRANSFER %d", line.TransferID)
+		case line.TransferID != 0 && line.Amount < 0:
+			add(":86:TRANSFER %d TO ACCOUNT %d", line.TransferID, line.CounterpartyAccountID)
+		case line.TransferID != 0:
+			add(":86:TRANSFER %d FROM ACCOUNT %d", line.TransferID, line.CounterpartyAccountID)
+		default:
+			add(":86:ENTRY %d", line.EntryID)
+		}
+	}
+
+	add(":62F:%s", mt940Balance(s.ClosingBalance, s.lastDay(), currency))
+	add("-")
+
+	_, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// ** mt940Balance renders a balance field: D/C mark, YYMMDD date, currency and amount
+func mt940Balance(amount int64, date time.Time, currency string) string {
+	return mt940CreditDebit(amount) + date.UTC().Format("060102") + currency + formatAmount(amount, currency, ",")
+}
+
+func mt940CreditDebit(amount int64) string {
+	if amount < 0 {
+		return "D"
+	}
+	return "C"
+}
+
+func mt940Reference(reference string) string {
+	if len(reference) > mt940ReferenceLength {
+		return reference[:mt940ReferenceLength]
+	}
+	return reference
+}
@@ -0,0 +1,161 @@
+package statement
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** Source is the part of the store a statement is built from
+type Source interface {
+	GetAccountActivity(ctx context.Context, arg db.GetAccountActivityParams) (db.AccountActivity, error)
+	CreateAccountStatement(ctx context.Context, arg db.CreateAccountStatementParams) (db.AccountStatement, error)
+}
+
+// ** Line is a single booked entry on the statement
+type Line struct {
+	EntryID               int64
+	TransferID            int64 // ** zero when the entry does not belong to a transfer
+	CounterpartyAccountID int64 // ** zero when the entry does not belong to a transfer or the counterparty is unknown
+	Fee                   bool  // ** the entry books the fee of the transfer rather than the transfer itself
+	Amount                int64 // ** negative for debits, positive for credits
+	BookedAt              time.Time
+}
+
+// ** Statement is the account activity over the half-open period [From, To).
+// ** Number counts the statements of the account in the order their periods were first issued.
+type Statement struct {
+	Number         int64
+	Account        db.Account
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	Lines          []Line
+	CreatedAt      time.Time
+}
+
+// ** Load builds the statement of an account from its entries and transfers, all read from one snapshot.
+// ** The opening balance is derived from the closing balance minus everything booked within the period.
+// ** The statement is only numbered once it is built, so a failed load does not use up a number.
+func Load(ctx context.Context, source Source, accountID int64, from, to time.Time) (Statement, error) {
+	if !from.Before(to) {
+		return Statement{}, fmt.Errorf("invalid statement period: %s is not before %s", from, to)
+	}
+
+	activity, err := source.GetAccountActivity(ctx, db.GetAccountActivityParams{
+		AccountID: accountID,
+		FromTime:  from,
+		ToTime:    to,
+	})
+	if err != nil {
+		return Statement{}, err
+	}
+
+	statement := Statement{
+		Account:        activity.Account,
+		From:           from,
+		To:             to,
+		ClosingBalance: activity.ClosingBalance,
+		Lines:          buildLines(accountID, activity.Entries, activity.Transfers),
+		CreatedAt:      time.Now(),
+	}
+	statement.OpeningBalance = statement.ClosingBalance
+	for _, line := range statement.Lines {
+		statement.OpeningBalance -= line.Amount
+	}
+
+	issued, err := source.CreateAccountStatement(ctx, db.CreateAccountStatementParams{
+		AccountID:   accountID,
+		PeriodStart: from,
+		PeriodEnd:   to,
+	})
+	if err != nil {
+		return Statement{}, err
+	}
+	statement.Number = issued.Number
+	return statement, nil
+}
+
+// ** buildLines links every entry to the transfer that booked it through its transfer_id.
+// ** A transfer books one entry of its amount on each side; any other entry it books is its fee.
+func buildLines(accountID int64, entries []db.Entry, transfers []db.Transfer) []Line {
+	byID := make(map[int64]db.Transfer, len(transfers))
+	for _, transfer := range transfers {
+		byID[transfer.ID] = transfer
+	}
+	principal := make(map[int64]bool)
+	lines := make([]Line, 0, len(entries))
+
+	for _, entry := range entries {
+		line := Line{
+			EntryID:  entry.ID,
+			Amount:   entry.Amount,
+			BookedAt: entry.CreatedAt,
+		}
+
+		if entry.TransferID.Valid {
+			line.TransferID = entry.TransferID.Int64
+			transfer, ok := byID[line.TransferID]
+			switch {
+			case ok && !principal[transfer.ID] && transfer.FromAccountID == accountID && entry.Amount == -transfer.Amount:
+				principal[transfer.ID] = true
+				line.CounterpartyAccountID = transfer.ToAccountID
+			case ok && !principal[transfer.ID] && transfer.ToAccountID == accountID && entry.Amount == transfer.Amount:
+				principal[transfer.ID] = true
+				line.CounterpartyAccountID = transfer.FromAccountID
+			default:
+				// ** the fee account is no party of the transfer: the fee account sees the sender
+				// **  as its counterparty, the sender's fee line has none
+				line.Fee = true
+				if ok && entry.Amount > 0 {
+					line.CounterpartyAccountID = transfer.FromAccountID
+				}
+			}
+		}
+
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// ** Credits returns the number and total of credit lines
+func (s Statement) Credits() (count int, total int64) {
+	for _, line := range s.Lines {
+		if line.Amount > 0 {
+			count++
+			total += line.Amount
+		}
+	}
+	return
+}
+
+// ** Debits returns the number and total (as a positive amount) of debit lines
+func (s Statement) Debits() (count int, total int64) {
+	for _, line := range s.Lines {
+		if line.Amount < 0 {
+			count++
+			total -= line.Amount
+		}
+	}
+	return
+}
+
+// ** lastDay is the last calendar day covered by the statement
+func (s Statement) lastDay() time.Time {
+	return s.To.Add(-time.Nanosecond)
+}
+
+// ** formatAmount renders the absolute value of an amount in minor units of the currency with the given decimal separator
+func formatAmount(amount int64, currency string, separator string) string {
+	c, err := money.LookupCurrency(currency)
+	if err != nil {
+		// ** accounts created before currencies were validated fall back to two decimals
+		c = money.Currency{Code: currency, Exponent: 2}
+	}
+	return strings.TrimPrefix(c.FormatAmount(amount, separator), "-")
+}
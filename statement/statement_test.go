@@ -0,0 +1,281 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// ** fakeSource serves a fixed ledger instead of a database
+type fakeSource struct {
+	accounts   map[int64]db.Account
+	entries    []db.Entry
+	transfers  []db.Transfer
+	statements []db.AccountStatement
+	err        error // ** returned by GetAccountActivity when set
+}
+
+// ** GetAccountActivity derives the closing balance like the query does: the current balance minus everything booked since
+func (f *fakeSource) GetAccountActivity(ctx context.Context, arg db.GetAccountActivityParams) (db.AccountActivity, error) {
+	if f.err != nil {
+		return db.AccountActivity{}, f.err
+	}
+	account, err := f.getAccount(arg.AccountID)
+	if err != nil {
+		return db.AccountActivity{}, err
+	}
+	return db.AccountActivity{
+		Account:        account,
+		ClosingBalance: account.Balance - f.sumEntriesSince(arg),
+		Entries:        f.listEntries(arg),
+		Transfers:      f.listTransfers(arg),
+	}, nil
+}
+
+func (f *fakeSource) getAccount(id int64) (db.Account, error) {
+	account, ok := f.accounts[id]
+	if !ok {
+		return db.Account{}, db.ErrRecordNotFound
+	}
+	return account, nil
+}
+
+func (f *fakeSource) listEntries(arg db.GetAccountActivityParams) []db.Entry {
+	var entries []db.Entry
+	for _, entry := range f.entries {
+		if entry.AccountID == arg.AccountID && !entry.CreatedAt.Before(arg.FromTime) && entry.CreatedAt.Before(arg.ToTime) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (f *fakeSource) listTransfers(arg db.GetAccountActivityParams) []db.Transfer {
+	booked := make(map[int64]bool)
+	for _, entry := range f.entries {
+		if entry.AccountID == arg.AccountID && entry.TransferID.Valid {
+			booked[entry.TransferID.Int64] = true
+		}
+	}
+
+	var transfers []db.Transfer
+	for _, transfer := range f.transfers {
+		if (transfer.FromAccountID == arg.AccountID || transfer.ToAccountID == arg.AccountID || booked[transfer.ID]) &&
+			!transfer.CreatedAt.Before(arg.FromTime) && transfer.CreatedAt.Before(arg.ToTime) {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers
+}
+
+func (f *fakeSource) sumEntriesSince(arg db.GetAccountActivityParams) int64 {
+	var total int64
+	for _, entry := range f.entries {
+		if entry.AccountID == arg.AccountID && !entry.CreatedAt.Before(arg.ToTime) {
+			total += entry.Amount
+		}
+	}
+	return total
+}
+
+// ** CreateAccountStatement numbers the periods of an account in the order they are first issued
+func (f *fakeSource) CreateAccountStatement(ctx context.Context, arg db.CreateAccountStatementParams) (db.AccountStatement, error) {
+	var number int64
+	for _, statement := range f.statements {
+		if statement.AccountID != arg.AccountID {
+			continue
+		}
+		if statement.PeriodStart.Equal(arg.PeriodStart) && statement.PeriodEnd.Equal(arg.PeriodEnd) {
+			return statement, nil
+		}
+		number = statement.Number
+	}
+
+	statement := db.AccountStatement{
+		ID:          int64(len(f.statements) + 1),
+		AccountID:   arg.AccountID,
+		Number:      number + 1,
+		PeriodStart: arg.PeriodStart,
+		PeriodEnd:   arg.PeriodEnd,
+	}
+	f.statements = append(f.statements, statement)
+	return statement, nil
+}
+
+// ** account 7 receives 120.00 from account 3, sends 45.50 to account 9 paying a 1.00 fee to account 5,
+// **  and receives 5.00 after the period
+func newFakeSource() *fakeSource {
+	day := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	t1 := day.Add(9 * time.Hour)
+	t2 := day.Add(24*time.Hour + 14*time.Hour)
+	t3 := day.Add(31 * 24 * time.Hour)
+
+	return &fakeSource{
+		accounts: map[int64]db.Account{
+			5: {ID: 5, Owner: "bank", Balance: 100, Currency: "EUR", CreatedAt: day},
+			7: {ID: 7, Owner: "alice", Balance: 107850, Currency: "EUR", CreatedAt: day},
+		},
+		entries: []db.Entry{
+			{ID: 11, AccountID: 3, Amount: -12000, CreatedAt: t1, TransferID: sql.NullInt64{Int64: 21, Valid: true}},
+			{ID: 12, AccountID: 7, Amount: 12000, CreatedAt: t1, TransferID: sql.NullInt64{Int64: 21, Valid: true}},
+			{ID: 13, AccountID: 7, Amount: -4550, CreatedAt: t2, TransferID: sql.NullInt64{Int64: 22, Valid: true}},
+			{ID: 14, AccountID: 9, Amount: 4550, CreatedAt: t2, TransferID: sql.NullInt64{Int64: 22, Valid: true}},
+			{ID: 15, AccountID: 7, Amount: 500, CreatedAt: t3},
+			{ID: 16, AccountID: 7, Amount: -100, CreatedAt: t2, TransferID: sql.NullInt64{Int64: 22, Valid: true}},
+			{ID: 17, AccountID: 5, Amount: 100, CreatedAt: t2, TransferID: sql.NullInt64{Int64: 22, Valid: true}},
+		},
+		transfers: []db.Transfer{
+			{ID: 21, FromAccountID: 3, ToAccountID: 7, Amount: 12000, CreatedAt: t1},
+			{ID: 22, FromAccountID: 7, ToAccountID: 9, Amount: 4550, CreatedAt: t2},
+		},
+	}
+}
+
+func loadTestStatement(t *testing.T) Statement {
+	from := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	statement, err := Load(context.Background(), newFakeSource(), 7, from, to)
+	require.NoError(t, err)
+	statement.CreatedAt = time.Date(2023, time.April, 1, 6, 30, 0, 0, time.UTC)
+	return statement
+}
+
+func TestLoad(t *testing.T) {
+	statement := loadTestStatement(t)
+
+	// ** 1078.50 now, minus 5.00 after the period, minus the net 73.50 within it
+	require.Equal(t, int64(107350), statement.ClosingBalance)
+	require.Equal(t, int64(100000), statement.OpeningBalance)
+	require.Equal(t, int64(1), statement.Number)
+
+	require.Len(t, statement.Lines, 3)
+	require.Equal(t, Line{EntryID: 12, TransferID: 21, CounterpartyAccountID: 3, Amount: 12000, BookedAt: statement.Lines[0].BookedAt}, statement.Lines[0])
+	require.Equal(t, Line{EntryID: 13, TransferID: 22, CounterpartyAccountID: 9, Amount: -4550, BookedAt: statement.Lines[1].BookedAt}, statement.Lines[1])
+	require.Equal(t, Line{EntryID: 16, TransferID: 22, Fee: true, Amount: -100, BookedAt: statement.Lines[2].BookedAt}, statement.Lines[2])
+}
+
+func TestLoadNumbering(t *testing.T) {
+	source := newFakeSource()
+	ctx := context.Background()
+	march := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	april := march.AddDate(0, 1, 0)
+
+	first, err := Load(ctx, source, 7, march, april)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), first.Number)
+
+	second, err := Load(ctx, source, 7, april, april.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), second.Number)
+
+	// ** reissuing a period keeps its number, and every account counts on its own
+	again, err := Load(ctx, source, 7, march, april)
+	require.NoError(t, err)
+	require.Equal(t, first.Number, again.Number)
+
+	other, err := Load(ctx, source, 5, march, april)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), other.Number)
+}
+
+func TestLoadFailureKeepsNumber(t *testing.T) {
+	source := newFakeSource()
+	ctx := context.Background()
+	march := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	april := march.AddDate(0, 1, 0)
+
+	// ** a statement that cannot be built is not numbered
+	source.err = errors.New("connection lost")
+	_, err := Load(ctx, source, 7, march, april)
+	require.ErrorIs(t, err, source.err)
+	require.Empty(t, source.statements)
+
+	source.err = nil
+	statement, err := Load(ctx, source, 7, march, april)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), statement.Number)
+}
+
+func TestLoadFeeAccount(t *testing.T) {
+	from := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	statement, err := Load(context.Background(), newFakeSource(), 5, from, to)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), statement.OpeningBalance)
+	require.Equal(t, int64(100), statement.ClosingBalance)
+
+	// ** the fee account is no party of transfer 22 but still sees its sender
+	require.Len(t, statement.Lines, 1)
+	require.Equal(t, Line{EntryID: 17, TransferID: 22, CounterpartyAccountID: 7, Fee: true, Amount: 100, BookedAt: statement.Lines[0].BookedAt}, statement.Lines[0])
+}
+
+func TestLoadInvalidPeriod(t *testing.T) {
+	now := time.Now()
+	_, err := Load(context.Background(), newFakeSource(), 7, now, now)
+	require.Error(t, err)
+}
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "0.00", formatAmount(0, "EUR", "."))
+	require.Equal(t, "0.05", formatAmount(5, "EUR", "."))
+	require.Equal(t, "1,00", formatAmount(-100, "EUR", ","))
+	require.Equal(t, "1234.56", formatAmount(123456, "EUR", "."))
+	require.Equal(t, "1234", formatAmount(1234, "JPY", "."))
+	require.Equal(t, "1.234", formatAmount(-1234, "KWD", "."))
+}
+
+func TestWriteCamt053(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCamt053(&buf, loadTestStatement(t)))
+	checkGolden(t, "camt053.golden.xml", buf.Bytes())
+	checkSchema(t, "camt.053.001.02.xsd", filepath.Join("testdata", "camt053.golden.xml"))
+
+	// ** the document must round-trip and keep the camt.053 namespace
+	var doc camtDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, Camt053Namespace, doc.XMLName.Space)
+	require.Len(t, doc.BkToCstmrStmt.Stmt.Ntry, 3)
+}
+
+func TestWriteMT940(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMT940(&buf, loadTestStatement(t)))
+	checkGolden(t, "mt940.golden.txt", buf.Bytes())
+}
+
+func checkGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, actual, 0644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+// ** checkSchema validates a document against an XML schema in testdata with xmllint
+func checkSchema(t *testing.T, schema string, path string) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed, skipping schema validation")
+	}
+
+	out, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", schema), path).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
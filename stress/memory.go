@@ -0,0 +1,152 @@
+package stress
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/money"
+)
+
+// ** MemoryStore keeps accounts, transfers and entries in memory. Like the Postgres store,
+// **  TransferTx locks both accounts in id order before it books anything.
+type MemoryStore struct {
+	mu        sync.Mutex
+	accounts  map[int64]*memoryAccount
+	transfers []db.Transfer
+	nextID    int64
+}
+
+type memoryAccount struct {
+	mu      sync.Mutex
+	account db.Account
+	entries []db.Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{accounts: make(map[int64]*memoryAccount)}
+}
+
+func (store *MemoryStore) newID() int64 {
+	store.nextID++
+	return store.nextID
+}
+
+func (store *MemoryStore) lookup(id int64) (*memoryAccount, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	account, ok := store.accounts[id]
+	if !ok {
+		return nil, fmt.Errorf("account %d: %w", id, db.ErrRecordNotFound)
+	}
+	return account, nil
+}
+
+func (store *MemoryStore) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	account := db.Account{
+		ID:        store.newID(),
+		Owner:     arg.Owner,
+		Balance:   arg.Balance,
+		Currency:  arg.Currency,
+		CreatedAt: time.Now(),
+		Status:    db.AccountStatusActive,
+		Version:   1,
+	}
+	// ** like the Postgres store, the opening balance is booked as an entry
+	memory := &memoryAccount{account: account}
+	if arg.Balance != 0 {
+		memory.entries = append(memory.entries, db.Entry{ID: store.newID(), AccountID: account.ID, Amount: arg.Balance, CreatedAt: account.CreatedAt})
+	}
+	store.accounts[account.ID] = memory
+	return account, nil
+}
+
+func (store *MemoryStore) GetAccount(ctx context.Context, id int64) (db.Account, error) {
+	account, err := store.lookup(id)
+	if err != nil {
+		return db.Account{}, err
+	}
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	return account.account, nil
+}
+
+func (store *MemoryStore) SumAccountEntriesSince(ctx context.Context, arg db.SumAccountEntriesSinceParams) (int64, error) {
+	account, err := store.lookup(arg.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	var total int64
+	for _, entry := range account.entries {
+		if !entry.CreatedAt.Before(arg.Since) {
+			total += entry.Amount
+		}
+	}
+	return total, nil
+}
+
+func (store *MemoryStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	from, err := store.lookup(arg.FromAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+	to, err := store.lookup(arg.ToAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	first, second := from, to
+	if arg.ToAccountID < arg.FromAccountID {
+		first, second = to, from
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	return store.book(arg, from, to)
+}
+
+// ** book records the transfer and its entries; the caller holds the locks of both accounts
+func (store *MemoryStore) book(arg db.TransferTxParams, from, to *memoryAccount) (db.TransferTxResult, error) {
+	for _, account := range []db.Account{from.account, to.account} {
+		if arg.Currency != "" && account.Currency != arg.Currency {
+			return db.TransferTxResult{}, fmt.Errorf("%w: account %d holds %s, transfer is in %s", money.ErrCurrencyMismatch, account.ID, account.Currency, arg.Currency)
+		}
+	}
+	if arg.Amount > from.account.Available() {
+		return db.TransferTxResult{}, fmt.Errorf("%w: account %d has %d available, %d needed", db.ErrInsufficientFunds, from.account.ID, from.account.Available(), arg.Amount)
+	}
+
+	now := time.Now()
+	store.mu.Lock()
+	transfer := db.Transfer{ID: store.newID(), FromAccountID: from.account.ID, ToAccountID: to.account.ID, Amount: arg.Amount, CreatedAt: now}
+	fromEntry := db.Entry{ID: store.newID(), AccountID: from.account.ID, Amount: -arg.Amount, CreatedAt: now}
+	toEntry := db.Entry{ID: store.newID(), AccountID: to.account.ID, Amount: arg.Amount, CreatedAt: now}
+	store.transfers = append(store.transfers, transfer)
+	store.mu.Unlock()
+
+	from.entries = append(from.entries, fromEntry)
+	to.entries = append(to.entries, toEntry)
+	from.account.Balance -= arg.Amount
+	from.account.Version++
+	to.account.Balance += arg.Amount
+	to.account.Version++
+
+	return db.TransferTxResult{
+		Transfer:    transfer,
+		FromAccount: from.account,
+		ToAccount:   to.account,
+		FromEntry:   fromEntry,
+		ToEntry:     toEntry,
+	}, nil
+}
@@ -0,0 +1,178 @@
+package stress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** Errors returned by Run
+var (
+	ErrDeadlock  = errors.New("transfers did not finish in time")
+	ErrTransfer  = errors.New("transfer failed")
+	ErrInvariant = errors.New("invariant violated")
+)
+
+// ** currency of the scenario accounts; the currencies migration enables it
+const currency = "EUR"
+
+// ** Store is the part of db.Store the harness drives
+type Store interface {
+	CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error)
+	GetAccount(ctx context.Context, id int64) (db.Account, error)
+	SumAccountEntriesSince(ctx context.Context, arg db.SumAccountEntriesSinceParams) (int64, error)
+	TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error)
+}
+
+// ** Run opens fresh accounts for the scenario, runs all its transfers concurrently and checks that:
+// **  every transfer succeeded and booked matching entries, or was refused for insufficient funds
+// **  by an account sending more than its opening balance in the scenario, no balance went negative,
+// **  the total balance is unchanged and every balance is the sum of the account's entries, opening balance included.
+// ** Transfers still running after timeout are reported as ErrDeadlock, and left behind.
+func Run(ctx context.Context, store Store, s Scenario, timeout time.Duration) error {
+	accounts := make([]db.Account, len(s.Balances))
+	for i, balance := range s.Balances {
+		account, err := store.CreateAccount(ctx, db.CreateAccountParams{
+			Owner:    fmt.Sprintf("stress%d", i),
+			Balance:  balance,
+			Currency: currency,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot open account %d: %w", i, err)
+		}
+		accounts[i] = account
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]db.TransferTxResult, len(s.Steps))
+	errs := make([]error, len(s.Steps))
+	steps := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < s.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range steps {
+				step := s.Steps[n]
+				results[n], errs[n] = store.TransferTx(ctx, db.TransferTxParams{
+					FromAccountID: accounts[step.From].ID,
+					ToAccountID:   accounts[step.To].ID,
+					Amount:        step.Amount,
+					Currency:      currency,
+				})
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for n := range s.Steps {
+			select {
+			case steps <- n:
+			case <-ctx.Done():
+				close(steps)
+				wg.Wait()
+				return
+			}
+		}
+		close(steps)
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("%w after %s", ErrDeadlock, timeout)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w after %s", ErrDeadlock, timeout)
+	}
+
+	sent := make([]int64, len(s.Balances))
+	for _, step := range s.Steps {
+		sent[step.From] += step.Amount
+	}
+	for n, err := range errs {
+		step := s.Steps[n]
+		switch {
+		case err == nil:
+		case !errors.Is(err, db.ErrInsufficientFunds):
+			return fmt.Errorf("%w: step %d: %v", ErrTransfer, n, err)
+		case sent[step.From] <= s.Balances[step.From]:
+			// ** the opening balance covers everything the account sends, in any order
+			return fmt.Errorf("%w: step %d refused although covered: %v", ErrTransfer, n, err)
+		}
+	}
+	return check(context.Background(), store, s, accounts, results, errs)
+}
+
+// ** check verifies the invariants once all transfers are done; refused transfers must not book anything
+func check(ctx context.Context, store Store, s Scenario, accounts []db.Account, results []db.TransferTxResult, errs []error) error {
+	expected := append([]int64(nil), s.Balances...)
+	for n, result := range results {
+		step := s.Steps[n]
+		from, to := accounts[step.From].ID, accounts[step.To].ID
+		if errs[n] != nil {
+			if result.Transfer.ID != 0 {
+				return fmt.Errorf("%w: refused step %d booked transfer %+v", ErrInvariant, n, result.Transfer)
+			}
+			continue
+		}
+		switch {
+		case result.Transfer.FromAccountID != from || result.Transfer.ToAccountID != to || result.Transfer.Amount != step.Amount:
+			return fmt.Errorf("%w: step %d booked transfer %+v", ErrInvariant, n, result.Transfer)
+		case result.FromEntry.AccountID != from || result.FromEntry.Amount != -step.Amount:
+			return fmt.Errorf("%w: step %d booked debit entry %+v", ErrInvariant, n, result.FromEntry)
+		case result.ToEntry.AccountID != to || result.ToEntry.Amount != step.Amount:
+			return fmt.Errorf("%w: step %d booked credit entry %+v", ErrInvariant, n, result.ToEntry)
+		case result.FromAccount.Balance < 0 || result.ToAccount.Balance < 0:
+			return fmt.Errorf("%w: step %d left balances %d and %d", ErrInvariant, n, result.FromAccount.Balance, result.ToAccount.Balance)
+		}
+		expected[step.From] -= step.Amount
+		expected[step.To] += step.Amount
+	}
+
+	var openingTotal, closingTotal int64
+	for i, account := range accounts {
+		final, err := store.GetAccount(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+		entries, err := store.SumAccountEntriesSince(ctx, db.SumAccountEntriesSinceParams{AccountID: account.ID})
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case final.Balance < 0:
+			return fmt.Errorf("%w: account %d has a negative balance of %d", ErrInvariant, i, final.Balance)
+		case final.Balance != expected[i]:
+			return fmt.Errorf("%w: account %d has a balance of %d instead of %d", ErrInvariant, i, final.Balance, expected[i])
+		case final.Balance != entries:
+			return fmt.Errorf("%w: account %d has a balance of %d but its entries, with the opening one, sum to %d", ErrInvariant, i, final.Balance, entries)
+		}
+		openingTotal += s.Balances[i]
+		closingTotal += final.Balance
+	}
+	if openingTotal != closingTotal {
+		return fmt.Errorf("%w: total balance went from %d to %d", ErrInvariant, openingTotal, closingTotal)
+	}
+	return nil
+}
+
+// ** Check runs the scenario up to attempts times and returns the first failure, for use with Shrink
+func Check(ctx context.Context, store Store, s Scenario, timeout time.Duration, attempts int) error {
+	for i := 0; i < attempts; i++ {
+		if err := Run(ctx, store, s, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
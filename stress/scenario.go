@@ -0,0 +1,107 @@
+// ** Package stress runs thousands of random concurrent transfers against a store and checks that
+// **  no money is created or lost. Failing scenarios are shrunk to a small one that still fails.
+package stress
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/techschool/simplebank/util"
+)
+
+// ** Config shapes the generated scenarios
+type Config struct {
+	Accounts    int
+	Transfers   int
+	Concurrency int
+	MaxBalance  int64
+
+	// ** Overspend lets accounts send more than their opening balance: which transfers are refused
+	// **  for insufficient funds then depends on the order they run in
+	Overspend bool
+}
+
+// ** DefaultConfig keeps many goroutines busy on few accounts, so most transfers contend
+var DefaultConfig = Config{
+	Accounts:    10,
+	Transfers:   2000,
+	Concurrency: 20,
+	MaxBalance:  100000,
+}
+
+// ** Step is one transfer between two accounts of the scenario, by index
+type Step struct {
+	From   int
+	To     int
+	Amount int64
+}
+
+// ** Scenario is a set of accounts with opening balances and the transfers run among them
+type Scenario struct {
+	Balances    []int64
+	Steps       []Step
+	Concurrency int
+}
+
+// ** Generate draws a scenario. Unless config.Overspend is set, no account sends more than its
+// **  opening balance, so every transfer is covered whatever order the transfers run in.
+func Generate(r *util.Rand, config Config) Scenario {
+	s := Scenario{
+		Balances:    make([]int64, config.Accounts),
+		Concurrency: config.Concurrency,
+	}
+	budgets := make([]int64, config.Accounts)
+	for i := range s.Balances {
+		s.Balances[i] = r.Int(0, config.MaxBalance)
+		budgets[i] = s.Balances[i]
+	}
+
+	if config.Accounts < 2 {
+		return s
+	}
+	last := int64(config.Accounts - 1)
+	for len(s.Steps) < config.Transfers {
+		from := int(r.Int(0, last))
+		if budgets[from] == 0 && !config.Overspend {
+			if !hasBudget(budgets) {
+				break
+			}
+			continue
+		}
+		to := int(r.Int(0, last-1))
+		if to >= from {
+			to++
+		}
+
+		var amount int64
+		if config.Overspend {
+			// ** up to a fifth of the opening balance each time, whatever was sent before
+			amount = r.AmountWithin(s.Balances[from]/5 + 1)
+		} else {
+			// ** spend at most a tenth of the budget at once, so accounts keep sending for a while
+			amount = r.AmountWithin(budgets[from]/10 + 1)
+			budgets[from] -= amount
+		}
+		s.Steps = append(s.Steps, Step{From: from, To: to, Amount: amount})
+	}
+	return s
+}
+
+func hasBudget(budgets []int64) bool {
+	for _, budget := range budgets {
+		if budget > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ** String renders the scenario so a failure can be read and replayed
+func (s Scenario) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d accounts with balances %v, %d transfers on %d goroutines:", len(s.Balances), s.Balances, len(s.Steps), s.Concurrency)
+	for _, step := range s.Steps {
+		fmt.Fprintf(&b, " %d->%d:%d", step.From, step.To, step.Amount)
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package stress
+
+// ** Shrink looks for a smaller scenario that still fails the check: first with chunks of transfers
+// **  removed, halving the chunk size down to single transfers, then with fewer goroutines, then
+// **  without the accounts no transfer uses. It returns the smallest failing scenario and its error.
+// ** Removing transfers never lets an account send more than it did, so a scenario in which every
+// **  transfer is covered stays covered.
+// ** Concurrency bugs do not fail every run, so check should run a scenario a few times.
+func Shrink(s Scenario, err error, check func(Scenario) error) (Scenario, error) {
+	for chunk := len(s.Steps) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start < len(s.Steps); {
+			candidate := s
+			candidate.Steps = append(append([]Step(nil), s.Steps[:start]...), s.Steps[min(start+chunk, len(s.Steps)):]...)
+			if candidateErr := check(candidate); candidateErr != nil {
+				s, err = candidate, candidateErr
+				continue
+			}
+			start += chunk
+		}
+	}
+
+	for s.Concurrency > 1 {
+		candidate := s
+		candidate.Concurrency = s.Concurrency / 2
+		candidateErr := check(candidate)
+		if candidateErr == nil {
+			break
+		}
+		s, err = candidate, candidateErr
+	}
+
+	if candidate := withoutUnusedAccounts(s); len(candidate.Balances) < len(s.Balances) {
+		if candidateErr := check(candidate); candidateErr != nil {
+			s, err = candidate, candidateErr
+		}
+	}
+	return s, err
+}
+
+// ** withoutUnusedAccounts drops the accounts no step uses and renumbers the rest
+func withoutUnusedAccounts(s Scenario) Scenario {
+	index := make(map[int]int)
+	var balances []int64
+	for i, balance := range s.Balances {
+		for _, step := range s.Steps {
+			if step.From == i || step.To == i {
+				index[i] = len(balances)
+				balances = append(balances, balance)
+				break
+			}
+		}
+	}
+
+	steps := make([]Step, len(s.Steps))
+	for n, step := range s.Steps {
+		steps[n] = Step{From: index[step.From], To: index[step.To], Amount: step.Amount}
+	}
+	return Scenario{Balances: balances, Steps: steps, Concurrency: s.Concurrency}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
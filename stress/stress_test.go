@@ -0,0 +1,216 @@
+package stress
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** runAndShrink runs a generated scenario and reports the shrunk scenario if it fails
+func runAndShrink(t *testing.T, store Store, config Config, timeout time.Duration) {
+	ctx := context.Background()
+	s := Generate(testRand, config)
+
+	err := Run(ctx, store, s, timeout)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, ErrDeadlock) && !errors.Is(err, ErrTransfer) && !errors.Is(err, ErrInvariant) {
+		t.Fatal(err)
+	}
+	t.Logf("shrinking failed scenario of %d transfers: %v", len(s.Steps), err)
+	s, err = Shrink(s, err, func(candidate Scenario) error {
+		return Check(ctx, store, candidate, timeout, 3)
+	})
+	t.Fatalf("%v\nsmallest failing scenario: %s", err, s)
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(testRand, DefaultConfig)
+	require.Len(t, s.Balances, DefaultConfig.Accounts)
+	require.Equal(t, DefaultConfig.Concurrency, s.Concurrency)
+	require.NotEmpty(t, s.Steps)
+
+	sent := make([]int64, len(s.Balances))
+	for _, step := range s.Steps {
+		require.NotEqual(t, step.From, step.To)
+		require.Positive(t, step.Amount)
+		sent[step.From] += step.Amount
+	}
+	for i, balance := range s.Balances {
+		require.LessOrEqual(t, sent[i], balance)
+	}
+}
+
+func TestGenerateOverspend(t *testing.T) {
+	config := DefaultConfig
+	config.Overspend = true
+	s := Generate(testRand, config)
+	require.Len(t, s.Steps, config.Transfers)
+
+	sent := make([]int64, len(s.Balances))
+	for _, step := range s.Steps {
+		require.NotEqual(t, step.From, step.To)
+		require.Positive(t, step.Amount)
+		sent[step.From] += step.Amount
+	}
+	overspent := 0
+	for i, balance := range s.Balances {
+		if sent[i] > balance {
+			overspent++
+		}
+	}
+	require.Positive(t, overspent)
+}
+
+func TestMemoryStore(t *testing.T) {
+	runAndShrink(t, NewMemoryStore(), DefaultConfig, 10*time.Second)
+}
+
+func TestMemoryStoreOverspend(t *testing.T) {
+	config := DefaultConfig
+	config.Overspend = true
+	runAndShrink(t, NewMemoryStore(), config, 10*time.Second)
+}
+
+func TestTransferTxPostgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs thousands of transactions")
+	}
+	config := DefaultConfig
+	config.Transfers = 500
+	runAndShrink(t, db.NewStore(testDB), config, time.Minute)
+
+	config.Overspend = true
+	runAndShrink(t, db.NewStore(testDB), config, time.Minute)
+}
+
+func TestShrink(t *testing.T) {
+	s := Generate(testRand, DefaultConfig)
+	culprit := len(s.Steps) / 3
+	s.Steps[culprit].Amount = -1
+
+	checks := 0
+	shrunk, err := Shrink(s, ErrInvariant, func(candidate Scenario) error {
+		checks++
+		for _, step := range candidate.Steps {
+			if step.Amount == -1 {
+				return ErrInvariant
+			}
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvariant)
+	require.Len(t, shrunk.Steps, 1)
+	require.Equal(t, int64(-1), shrunk.Steps[0].Amount)
+	require.Equal(t, 1, shrunk.Concurrency)
+	require.Len(t, shrunk.Balances, 2)
+	require.Equal(t, s.Balances[s.Steps[culprit].From], shrunk.Balances[shrunk.Steps[0].From])
+	require.Less(t, checks, len(s.Steps))
+}
+
+// ** lostUpdateStore reads the balances, yields, then writes them back: concurrent transfers overwrite each other
+type lostUpdateStore struct {
+	*MemoryStore
+}
+
+func (store lostUpdateStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	from, err := store.lookup(arg.FromAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+	to, err := store.lookup(arg.ToAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	fromBalance, toBalance := store.balance(from), store.balance(to)
+	runtime.Gosched()
+
+	result, err := store.MemoryStore.TransferTx(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+	from.mu.Lock()
+	from.account.Balance = fromBalance - arg.Amount
+	from.mu.Unlock()
+	to.mu.Lock()
+	to.account.Balance = toBalance + arg.Amount
+	to.mu.Unlock()
+	return result, nil
+}
+
+func (store lostUpdateStore) balance(account *memoryAccount) int64 {
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	return account.account.Balance
+}
+
+func TestLostUpdateIsShrunk(t *testing.T) {
+	ctx := context.Background()
+	store := lostUpdateStore{NewMemoryStore()}
+	s := Generate(testRand, DefaultConfig)
+
+	err := Check(ctx, store, s, 10*time.Second, 5)
+	require.ErrorIs(t, err, ErrInvariant)
+
+	shrunk, err := Shrink(s, err, func(candidate Scenario) error {
+		return Check(ctx, store, candidate, 10*time.Second, 20)
+	})
+	require.ErrorIs(t, err, ErrInvariant)
+	require.Less(t, len(shrunk.Steps), len(s.Steps))
+	require.GreaterOrEqual(t, len(shrunk.Steps), 2)
+}
+
+// ** unorderedLockStore locks the sender before the receiver, so opposite transfers deadlock
+type unorderedLockStore struct {
+	*MemoryStore
+}
+
+func (store unorderedLockStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	from, err := store.lookup(arg.FromAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+	to, err := store.lookup(arg.ToAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	from.mu.Lock()
+	defer from.mu.Unlock()
+	runtime.Gosched()
+	to.mu.Lock()
+	defer to.mu.Unlock()
+	return store.book(arg, from, to)
+}
+
+func TestDeadlockIsDetected(t *testing.T) {
+	config := Config{Accounts: 2, Transfers: 200, Concurrency: 8, MaxBalance: 100000}
+	s := Generate(testRand, config)
+
+	err := Check(context.Background(), unorderedLockStore{NewMemoryStore()}, s, 200*time.Millisecond, 5)
+	require.ErrorIs(t, err, ErrDeadlock)
+}
+
+// ** refusingStore refuses every transfer for insufficient funds
+type refusingStore struct {
+	*MemoryStore
+}
+
+func (store refusingStore) TransferTx(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+	return db.TransferTxResult{}, db.ErrInsufficientFunds
+}
+
+func TestCoveredTransferRefused(t *testing.T) {
+	s := Generate(testRand, Config{Accounts: 2, Transfers: 10, Concurrency: 2, MaxBalance: 100000})
+	require.NotEmpty(t, s.Steps)
+
+	err := Run(context.Background(), refusingStore{NewMemoryStore()}, s, time.Second)
+	require.ErrorIs(t, err, ErrTransfer)
+}
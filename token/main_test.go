@@ -0,0 +1,18 @@
+package token
+
+import (
+	"os"
+	"testing"
+
+	"github.com/techschool/simplebank/util"
+)
+
+var seed = util.SeedFlag()
+
+var testRand *util.Rand
+
+func TestMain(m *testing.M) {
+	os.Exit(util.RunWithSeed(m, seed, func(r *util.Rand) {
+		testRand = r
+	}))
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ** Config stores all configuration of the application.
+// ** The values are read by viper from a config file or environment variables.
+type Config struct {
+	DBSource              string        `mapstructure:"DB_SOURCE"`
+	DBReplicaSource       string        `mapstructure:"DB_REPLICA_SOURCE"`
+	DBMaxConns            int32         `mapstructure:"DB_MAX_CONNS"`
+	DBMinConns            int32         `mapstructure:"DB_MIN_CONNS"`
+	DBMaxConnLifetime     time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME"`
+	DBMaxConnIdleTime     time.Duration `mapstructure:"DB_MAX_CONN_IDLE_TIME"`
+	DBHealthCheckPeriod   time.Duration `mapstructure:"DB_HEALTH_CHECK_PERIOD"`
+	DBQueryExecMode       string        `mapstructure:"DB_QUERY_EXEC_MODE"`
+	DBStatementCacheSize  int           `mapstructure:"DB_STATEMENT_CACHE_SIZE"`
+	ServerAddress         string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration   time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration  time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	PublicURL             string        `mapstructure:"PUBLIC_URL"`
+	SMTPAddress           string        `mapstructure:"SMTP_ADDRESS"`
+	SMTPUsername          string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword          string        `mapstructure:"SMTP_PASSWORD"`
+	EmailSenderAddress    string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	VerifyEmailDuration   time.Duration `mapstructure:"VERIFY_EMAIL_DURATION"`
+	PasswordResetDuration time.Duration `mapstructure:"PASSWORD_RESET_DURATION"`
+}
+
+// ** LoadConfig reads configuration from app.env in path, overridden by environment variables
+func LoadConfig(path string) (config Config, err error) {
+	viper.AddConfigPath(path)
+	viper.SetConfigName("app")
+	viper.SetConfigType("env")
+
+	viper.AutomaticEnv()
+
+	err = viper.ReadInConfig()
+	if err != nil {
+		return
+	}
+
+	err = viper.Unmarshal(&config)
+	return
+}
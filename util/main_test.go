@@ -0,0 +1,16 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+var seed = SeedFlag()
+
+var testRand *Rand
+
+func TestMain(m *testing.M) {
+	os.Exit(RunWithSeed(m, seed, func(r *Rand) {
+		testRand = r
+	}))
+}
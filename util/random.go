@@ -1,52 +1,148 @@
 package util
 
 import (
+	"flag"
+	"fmt"
+	"log"
 	"math/rand"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-const alphabet = "abcdefghijklmnopqrstuvwxyz"
+const (
+	alphabet = "abcdefghijklmnopqrstuvwxyz"
+	digits   = "0123456789"
+)
+
+// ** SeedEnv fixes the seed of random test data, e.g. SIMPLEBANK_SEED=42 go test ./...
+const SeedEnv = "SIMPLEBANK_SEED"
+
+// ** currencies enabled by the currencies migration
+var currencies = []string{"EUR", "USD", "CAD", "TRY"}
+
+// ** Rand generates random test data from its own seed, so a failing run can be replayed.
+// ** It is safe for concurrent use.
+type Rand struct {
+	seed int64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// ** NewRand creates a generator that always yields the same data for the same seed
+func NewRand(seed int64) *Rand {
+	return &Rand{seed: seed, rnd: rand.New(rand.NewSource(seed))}
+}
+
+// ** NewTestRand creates the generator of a test run. The seed is taken from the first non-zero of:
+// **  the given seed (usually a -seed test flag), the SeedEnv environment variable, the clock.
+func NewTestRand(seed int64) (*Rand, error) {
+	if seed == 0 {
+		if value := os.Getenv(SeedEnv); value != "" {
+			var err error
+			seed, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", SeedEnv, value, err)
+			}
+		}
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return NewRand(seed), nil
+}
+
+// ** SeedFlag registers the -seed flag of a test binary, to be passed to RunWithSeed
+func SeedFlag() *int64 {
+	return flag.Int64("seed", 0, "seed of the random test data, 0 takes "+SeedEnv+" or the clock")
+}
+
+// ** RunWithSeed is the body of a TestMain. It creates the generator of the run with NewTestRand(*seed),
+// **  hands it to setup before the tests run and returns their exit code.
+// ** When a test fails it logs the seed to replay the run with.
+func RunWithSeed(m interface{ Run() int }, seed *int64, setup func(*Rand)) int {
+	flag.Parse()
 
-// ** Initialization
-func init() {
-	rand.Seed(time.Now().UnixMicro())
+	r, err := NewTestRand(*seed)
+	if err != nil {
+		log.Fatal("cannot seed test data:", err)
+	}
+	setup(r)
+
+	code := m.Run()
+	if code != 0 {
+		log.Printf("random test data was generated with seed %d, replay it with -seed=%d", r.Seed(), r.Seed())
+	}
+	return code
+}
+
+// ** Seed returns the seed to replay the generator with
+func (r *Rand) Seed() int64 {
+	return r.seed
+}
+
+// ** Int returns a number in [min, max]
+func (r *Rand) Int(min, max int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return min + r.rnd.Int63n(max-min+1)
 }
 
-// ** Random Int Generator
-func RandomInt(min, max int64) int64 {
-	return min + rand.Int63n(max-min+1)
+// ** String returns n random lowercase letters
+func (r *Rand) String(n int) string {
+	return r.fromAlphabet(alphabet, n)
 }
 
-// ** Random String Generator
-func RandomString(n int) string {
+func (r *Rand) fromAlphabet(chars string, n int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	var stringBuilder strings.Builder
-	k := len(alphabet)
 	for i := 0; i < n; i++ {
-		c := alphabet[rand.Intn(k)]
-		stringBuilder.WriteByte(c)
+		stringBuilder.WriteByte(chars[r.rnd.Intn(len(chars))])
 	}
 	return stringBuilder.String()
 }
 
-// ** Random Owner Generator
-func RandomOwner() string {
-	return RandomString(6)
+// ** Owner returns a valid username: a letter followed by 7 letters or digits
+func (r *Rand) Owner() string {
+	return r.String(1) + r.fromAlphabet(alphabet+digits, 7)
 }
 
-// ** Random Money Generator
-func RandomMoney() int64 {
-	return RandomInt(0, 1000)
+// ** FullName returns a capitalized first and last name
+func (r *Rand) FullName() string {
+	first, last := r.String(6), r.String(8)
+	return strings.ToUpper(first[:1]) + first[1:] + " " + strings.ToUpper(last[:1]) + last[1:]
 }
 
-// ** Random Currency Generator
-func RandomCurrency() string {
-	currencies := []string{"EUR", "USD", "CAD", "TRY"}
-	n := len(currencies)
-	return currencies[rand.Intn(n)]
+// ** Email returns a valid email address
+func (r *Rand) Email() string {
+	return fmt.Sprintf("%s@%s.com", r.Owner(), r.String(5))
 }
 
-// ** Random Amount Generator
-func RandomAmount() int64 {
-	return RandomInt(-1000, 1000) // can be negative or positive
+// ** Currency returns a currency that is enabled, so accounts can be opened in it
+func (r *Rand) Currency() string {
+	return currencies[r.Int(0, int64(len(currencies)-1))]
+}
+
+// ** Money returns an opening balance in [0, 1000]
+func (r *Rand) Money() int64 {
+	return r.Int(0, 1000)
+}
+
+// ** Amount returns an entry amount in [-1000, 1000], it can be negative or positive
+func (r *Rand) Amount() int64 {
+	return r.Int(-1000, 1000)
+}
+
+// ** AmountWithin returns a transfer amount the balance covers, in [1, balance].
+// ** It returns 0 when the balance does not cover any.
+func (r *Rand) AmountWithin(balance int64) int64 {
+	if balance < 1 {
+		return 0
+	}
+	return r.Int(1, balance)
 }
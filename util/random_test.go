@@ -0,0 +1,63 @@
+package util
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandReplay(t *testing.T) {
+	r1 := NewRand(42)
+	r2 := NewRand(42)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, r1.Owner(), r2.Owner())
+		require.Equal(t, r1.Email(), r2.Email())
+		require.Equal(t, r1.Amount(), r2.Amount())
+	}
+	require.Equal(t, int64(42), r1.Seed())
+
+	require.NotEqual(t, NewRand(1).String(16), NewRand(2).String(16))
+}
+
+func TestNewTestRand(t *testing.T) {
+	t.Setenv(SeedEnv, "7")
+
+	r, err := NewTestRand(3)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), r.Seed())
+
+	r, err = NewTestRand(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), r.Seed())
+
+	t.Setenv(SeedEnv, "")
+	r, err = NewTestRand(0)
+	require.NoError(t, err)
+	require.NotZero(t, r.Seed())
+
+	t.Setenv(SeedEnv, "seven")
+	_, err = NewTestRand(0)
+	require.Error(t, err)
+}
+
+func TestRandFactories(t *testing.T) {
+	r := NewRand(1)
+	owner := regexp.MustCompile(`^[a-z][a-z0-9]{7}$`)
+	email := regexp.MustCompile(`^[a-z][a-z0-9]{7}@[a-z]{5}\.com$`)
+
+	for i := 0; i < 100; i++ {
+		require.Regexp(t, owner, r.Owner())
+		require.Regexp(t, email, r.Email())
+		require.Contains(t, currencies, r.Currency())
+
+		balance := r.Money()
+		require.True(t, balance >= 0 && balance <= 1000)
+		if amount := r.AmountWithin(balance); balance > 0 {
+			require.True(t, amount >= 1 && amount <= balance)
+		} else {
+			require.Zero(t, amount)
+		}
+	}
+	require.Zero(t, r.AmountWithin(-5))
+}
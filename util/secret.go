@@ -0,0 +1,19 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+const secretCodeBytes = 32
+
+// ** NewSecretCode returns a random code for links sent by email, such as email verification.
+// ** Unlike the Random* helpers it uses crypto/rand, so the code cannot be guessed.
+func NewSecretCode() (string, error) {
+	b := make([]byte, secretCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret code: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
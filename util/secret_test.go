@@ -0,0 +1,21 @@
+package util
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSecretCode(t *testing.T) {
+	code1, err := NewSecretCode()
+	require.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(code1)
+	require.NoError(t, err)
+	require.Len(t, decoded, secretCodeBytes)
+
+	code2, err := NewSecretCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code1, code2)
+}
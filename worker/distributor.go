@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** TaskDistributor is an interface for enqueuing tasks to be run by a TaskProcessor
+type TaskDistributor interface {
+	// ** DistributeTaskSendVerifyEmail enqueues the email that verifies the address of a new user
+	DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...Option) (db.Task, error)
+
+	// ** DistributeTaskSendPasswordReset enqueues the email carrying a password reset code
+	DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...Option) (db.Task, error)
+
+	// ** WithTx returns a distributor that enqueues in the transaction of q.
+	// ** Its tasks only become visible to processors if the transaction commits.
+	WithTx(q *db.Queries) TaskDistributor
+}
+
+// ** Enqueuer is the part of db.Store, or of the db.Queries of a transaction, the distributor needs
+type Enqueuer interface {
+	CreateTask(ctx context.Context, arg db.CreateTaskParams) (db.Task, error)
+}
+
+// ** PGTaskDistributor enqueues tasks in the tasks table of Postgres
+type PGTaskDistributor struct {
+	enqueuer Enqueuer
+}
+
+// ** NewPGTaskDistributor creates a new PGTaskDistributor
+func NewPGTaskDistributor(enqueuer Enqueuer) TaskDistributor {
+	return &PGTaskDistributor{enqueuer: enqueuer}
+}
+
+func (distributor *PGTaskDistributor) WithTx(q *db.Queries) TaskDistributor {
+	return &PGTaskDistributor{enqueuer: q}
+}
+
+func (distributor *PGTaskDistributor) distribute(ctx context.Context, taskType string, payload interface{}, opts []Option) (db.Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return db.Task{}, fmt.Errorf("failed to marshal %s payload: %w", taskType, err)
+	}
+
+	options := newOptions(time.Now(), opts)
+	task, err := distributor.enqueuer.CreateTask(ctx, db.CreateTaskParams{
+		Type:        taskType,
+		Payload:     data,
+		MaxAttempts: options.maxAttempts,
+		RunAt:       options.runAt,
+	})
+	if err != nil {
+		return db.Task{}, fmt.Errorf("failed to enqueue %s: %w", taskType, err)
+	}
+	return task, nil
+}
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+func TestDistributeTask(t *testing.T) {
+	store := newFakeStore(time.Now)
+	distributor := NewPGTaskDistributor(store)
+
+	before := time.Now()
+	task, err := distributor.DistributeTaskSendVerifyEmail(context.Background(), &PayloadSendVerifyEmail{VerifyEmailID: 7, SecretCode: "abc"})
+	require.NoError(t, err)
+	require.Equal(t, TaskSendVerifyEmail, task.Type)
+	require.JSONEq(t, `{"verify_email_id":7,"secret_code":"abc"}`, string(task.Payload))
+	require.Equal(t, db.TaskStatusPending, task.Status)
+	require.Equal(t, int32(DefaultMaxAttempts), task.MaxAttempts)
+	require.WithinDuration(t, before, task.RunAt, time.Second)
+
+	task, err = distributor.DistributeTaskSendPasswordReset(context.Background(), &PayloadSendPasswordReset{PasswordResetID: 9, SecretCode: "abc"},
+		MaxAttempts(2), ProcessIn(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, TaskSendPasswordReset, task.Type)
+	require.JSONEq(t, `{"password_reset_id":9,"secret_code":"abc"}`, string(task.Payload))
+	require.Equal(t, int32(2), task.MaxAttempts)
+	require.WithinDuration(t, before.Add(time.Hour), task.RunAt, time.Second)
+}
+
+func TestOptions(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	at := now.Add(24 * time.Hour)
+
+	o := newOptions(now, nil)
+	require.Equal(t, int32(DefaultMaxAttempts), o.maxAttempts)
+	require.Equal(t, now, o.runAt)
+
+	o = newOptions(now, []Option{ProcessAt(at), MaxAttempts(0)})
+	require.Equal(t, int32(DefaultMaxAttempts), o.maxAttempts)
+	require.Equal(t, at, o.runAt)
+
+	o = newOptions(now, []Option{ProcessIn(time.Minute), MaxAttempts(1)})
+	require.Equal(t, int32(1), o.maxAttempts)
+	require.Equal(t, now.Add(time.Minute), o.runAt)
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+)
+
+// ** fakeStore keeps tasks, users, verifications and resets in memory.
+// ** ClaimTask follows the rules of the SQL query: due pending tasks, or running tasks whose lease expired.
+type fakeStore struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	tasks  map[int64]*db.Task
+	nextID int64
+
+	users          map[string]db.User
+	verifyEmails   map[int64]db.VerifyEmail
+	passwordResets map[int64]db.PasswordReset
+}
+
+func newFakeStore(now func() time.Time) *fakeStore {
+	return &fakeStore{
+		now:            now,
+		tasks:          map[int64]*db.Task{},
+		users:          map[string]db.User{},
+		verifyEmails:   map[int64]db.VerifyEmail{},
+		passwordResets: map[int64]db.PasswordReset{},
+	}
+}
+
+func (store *fakeStore) CreateTask(ctx context.Context, arg db.CreateTaskParams) (db.Task, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.nextID++
+	task := &db.Task{
+		ID:          store.nextID,
+		Type:        arg.Type,
+		Payload:     arg.Payload,
+		Status:      db.TaskStatusPending,
+		MaxAttempts: arg.MaxAttempts,
+		RunAt:       arg.RunAt,
+		CreatedAt:   store.now(),
+	}
+	store.tasks[task.ID] = task
+	return *task, nil
+}
+
+func (store *fakeStore) ClaimTask(ctx context.Context, leaseSeconds float64) (db.Task, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := store.now()
+	var due []*db.Task
+	for _, task := range store.tasks {
+		if (task.Status == db.TaskStatusPending && !task.RunAt.After(now)) ||
+			(task.Status == db.TaskStatusRunning && task.LockedUntil.Time.Before(now)) {
+			due = append(due, task)
+		}
+	}
+	if len(due) == 0 {
+		return db.Task{}, db.ErrRecordNotFound
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].RunAt.Before(due[j].RunAt) })
+
+	task := due[0]
+	task.Status = db.TaskStatusRunning
+	task.Attempts++
+	task.LockedUntil = sql.NullTime{Time: now.Add(time.Duration(leaseSeconds * float64(time.Second))), Valid: true}
+	return *task, nil
+}
+
+// ** leased returns the task if it still runs under the lease, like the WHERE clause of the SQL queries
+func (store *fakeStore) leased(id int64, lockedUntil sql.NullTime) *db.Task {
+	task := store.tasks[id]
+	if task.Status != db.TaskStatusRunning || !task.LockedUntil.Time.Equal(lockedUntil.Time) {
+		return nil
+	}
+	return task
+}
+
+func (store *fakeStore) CompleteTask(ctx context.Context, arg db.CompleteTaskParams) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	task := store.leased(arg.ID, arg.LockedUntil)
+	if task == nil {
+		return 0, nil
+	}
+	task.Status = db.TaskStatusDone
+	task.Payload = json.RawMessage(`{}`)
+	task.LockedUntil = sql.NullTime{}
+	task.CompletedAt = sql.NullTime{Time: store.now(), Valid: true}
+	return 1, nil
+}
+
+func (store *fakeStore) RetryTask(ctx context.Context, arg db.RetryTaskParams) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	task := store.leased(arg.ID, arg.LockedUntil)
+	if task == nil {
+		return 0, nil
+	}
+	task.Status = db.TaskStatusPending
+	task.LockedUntil = sql.NullTime{}
+	task.RunAt = arg.RunAt
+	task.LastError = arg.LastError
+	return 1, nil
+}
+
+func (store *fakeStore) KillTask(ctx context.Context, arg db.KillTaskParams) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	task := store.leased(arg.ID, arg.LockedUntil)
+	if task == nil {
+		return 0, nil
+	}
+	task.Status = db.TaskStatusDead
+	task.Payload = json.RawMessage(`{}`)
+	task.LockedUntil = sql.NullTime{}
+	task.LastError = arg.LastError
+	return 1, nil
+}
+
+func (store *fakeStore) task(id int64) db.Task {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return *store.tasks[id]
+}
+
+func (store *fakeStore) GetUser(ctx context.Context, username string) (db.User, error) {
+	user, ok := store.users[username]
+	if !ok {
+		return db.User{}, db.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (store *fakeStore) GetVerifyEmail(ctx context.Context, id int64) (db.VerifyEmail, error) {
+	verifyEmail, ok := store.verifyEmails[id]
+	if !ok {
+		return db.VerifyEmail{}, db.ErrRecordNotFound
+	}
+	return verifyEmail, nil
+}
+
+func (store *fakeStore) GetPasswordReset(ctx context.Context, id int64) (db.PasswordReset, error) {
+	reset, ok := store.passwordResets[id]
+	if !ok {
+		return db.PasswordReset{}, db.ErrRecordNotFound
+	}
+	return reset, nil
+}
+
+// ** fakeClock is a settable clock shared by the fake store and the processor
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Add(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
@@ -0,0 +1,45 @@
+package worker
+
+import "time"
+
+// ** DefaultMaxAttempts is how often a task is tried before it is given up
+const DefaultMaxAttempts = 5
+
+type options struct {
+	maxAttempts int32
+	runAt       time.Time
+}
+
+// ** Option changes how a task is enqueued
+type Option func(*options)
+
+// ** MaxAttempts sets how often the task is tried before it is given up
+func MaxAttempts(n int32) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxAttempts = n
+		}
+	}
+}
+
+// ** ProcessAt schedules the task to run no earlier than t
+func ProcessAt(t time.Time) Option {
+	return func(o *options) {
+		o.runAt = t
+	}
+}
+
+// ** ProcessIn schedules the task to run no earlier than d from now
+func ProcessIn(d time.Duration) Option {
+	return func(o *options) {
+		o.runAt = o.runAt.Add(d)
+	}
+}
+
+func newOptions(now time.Time, opts []Option) options {
+	o := options{maxAttempts: DefaultMaxAttempts, runAt: now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,263 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/mail"
+)
+
+// ** ErrSkipRetry makes a failed task dead at once instead of being retried.
+// ** Wrap it in errors that retrying cannot fix, such as a malformed payload.
+var ErrSkipRetry = errors.New("skip retry")
+
+// ** errLeaseExpired is returned when a task ran past its lease: another worker may have claimed it
+// **  meanwhile, so the outcome of this run is not recorded
+var errLeaseExpired = errors.New("lease expired before the task finished")
+
+// ** TaskProcessor is an interface for running enqueued tasks in the background
+type TaskProcessor interface {
+	// ** Start starts polling for tasks and returns at once
+	Start() error
+
+	// ** Shutdown stops claiming tasks and waits for the running ones to finish.
+	// ** When ctx is done first, the running tasks are cancelled and will be retried.
+	Shutdown(ctx context.Context) error
+}
+
+// ** Store is the part of db.Store the processor and its task handlers need
+type Store interface {
+	ClaimTask(ctx context.Context, leaseSeconds float64) (db.Task, error)
+	CompleteTask(ctx context.Context, arg db.CompleteTaskParams) (int64, error)
+	RetryTask(ctx context.Context, arg db.RetryTaskParams) (int64, error)
+	KillTask(ctx context.Context, arg db.KillTaskParams) (int64, error)
+
+	GetUser(ctx context.Context, username string) (db.User, error)
+	GetVerifyEmail(ctx context.Context, id int64) (db.VerifyEmail, error)
+	GetPasswordReset(ctx context.Context, id int64) (db.PasswordReset, error)
+}
+
+// ** HandlerFunc runs a task. A nil error completes it; any other error retries it,
+// **  unless it wraps ErrSkipRetry or the task has no attempts left.
+type HandlerFunc func(ctx context.Context, task db.Task) error
+
+// ** ProcessorConfig tunes a PGTaskProcessor
+type ProcessorConfig struct {
+	// ** Concurrency is the number of tasks run at the same time
+	Concurrency int
+	// ** PollInterval is how long an idle worker waits before looking for tasks again
+	PollInterval time.Duration
+	// ** Lease is how long a task may run. A task still running after its lease,
+	// **  e.g. because its worker crashed, is claimed again by another worker.
+	// ** The lease is counted by the database clock, the one ClaimTask compares it with.
+	Lease time.Duration
+	// ** Backoff returns the delay before the next try of a task that failed its nth attempt
+	Backoff func(attempt int32) time.Duration
+}
+
+// ** DefaultProcessorConfig is suited to sending emails
+var DefaultProcessorConfig = ProcessorConfig{
+	Concurrency:  4,
+	PollInterval: time.Second,
+	Lease:        time.Minute,
+	Backoff:      DefaultBackoff,
+}
+
+// ** DefaultBackoff doubles the delay on every attempt: 10s, 20s, 40s, ... up to an hour
+func DefaultBackoff(attempt int32) time.Duration {
+	const base, max = 10 * time.Second, time.Hour
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := base
+	for i := int32(1); i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+// ** PGTaskProcessor runs the tasks of the Postgres tasks table.
+// ** Workers claim tasks with SELECT ... FOR UPDATE SKIP LOCKED, so several processors,
+// **  in one or many server instances, never run the same task at the same time.
+type PGTaskProcessor struct {
+	store     Store
+	mailer    mail.Sender
+	publicURL string
+	config    ProcessorConfig
+	handlers  map[string]HandlerFunc
+	now       func() time.Time
+
+	stop           chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+	handlerCtx     context.Context
+	cancelHandlers context.CancelFunc
+}
+
+// ** NewPGTaskProcessor creates a processor for the tasks of this package.
+// ** publicURL is where users reach the server, used for links in emails.
+func NewPGTaskProcessor(store Store, mailer mail.Sender, publicURL string, config ProcessorConfig) TaskProcessor {
+	return newPGTaskProcessor(store, mailer, publicURL, config)
+}
+
+func newPGTaskProcessor(store Store, mailer mail.Sender, publicURL string, config ProcessorConfig) *PGTaskProcessor {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if config.Backoff == nil {
+		config.Backoff = DefaultBackoff
+	}
+
+	handlerCtx, cancelHandlers := context.WithCancel(context.Background())
+	processor := &PGTaskProcessor{
+		store:          store,
+		mailer:         mailer,
+		publicURL:      publicURL,
+		config:         config,
+		now:            time.Now,
+		stop:           make(chan struct{}),
+		handlerCtx:     handlerCtx,
+		cancelHandlers: cancelHandlers,
+	}
+	processor.handlers = map[string]HandlerFunc{
+		TaskSendVerifyEmail:   processor.ProcessTaskSendVerifyEmail,
+		TaskSendPasswordReset: processor.ProcessTaskSendPasswordReset,
+	}
+	return processor
+}
+
+func (processor *PGTaskProcessor) Start() error {
+	if processor.config.Lease <= 0 || processor.config.PollInterval <= 0 {
+		return errors.New("task processor needs a positive lease and poll interval")
+	}
+
+	for i := 0; i < processor.config.Concurrency; i++ {
+		processor.wg.Add(1)
+		go processor.run()
+	}
+	log.Printf("worker: processing tasks with %d workers", processor.config.Concurrency)
+	return nil
+}
+
+func (processor *PGTaskProcessor) Shutdown(ctx context.Context) error {
+	processor.stopOnce.Do(func() { close(processor.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		processor.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		processor.cancelHandlers()
+		return nil
+	case <-ctx.Done():
+		processor.cancelHandlers()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// ** run is the loop of one worker: it processes tasks as long as there are some due,
+// **  and polls every PollInterval otherwise
+func (processor *PGTaskProcessor) run() {
+	defer processor.wg.Done()
+
+	for {
+		select {
+		case <-processor.stop:
+			return
+		default:
+		}
+
+		processed, err := processor.processNext()
+		if err != nil {
+			log.Printf("worker: %v", err)
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-processor.stop:
+			return
+		case <-time.After(processor.config.PollInterval):
+		}
+	}
+}
+
+// ** processNext claims a due task and runs it. It returns false if no task was due.
+func (processor *PGTaskProcessor) processNext() (bool, error) {
+	// ** the bookkeeping uses its own context, so a task finishing during shutdown is still recorded
+	ctx := context.Background()
+
+	task, err := processor.store.ClaimTask(ctx, processor.config.Lease.Seconds())
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot claim task: %w", err)
+	}
+
+	return true, processor.finish(ctx, task, processor.handle(task))
+}
+
+// ** handle runs the handler of the task within its lease, turning panics into errors
+func (processor *PGTaskProcessor) handle(task db.Task) (err error) {
+	handler, ok := processor.handlers[task.Type]
+	if !ok {
+		return fmt.Errorf("%w: unknown task type %q", ErrSkipRetry, task.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(processor.handlerCtx, processor.config.Lease)
+	defer cancel()
+	return handler(ctx, task)
+}
+
+// ** finish records the outcome of a task: done, retried after a backoff, or dead.
+// ** It only does so while the task still holds the lease of this run.
+func (processor *PGTaskProcessor) finish(ctx context.Context, task db.Task, taskErr error) error {
+	if taskErr == nil {
+		updated, err := processor.store.CompleteTask(ctx, db.CompleteTaskParams{ID: task.ID, LockedUntil: task.LockedUntil})
+		return finished(task, "complete", updated, err)
+	}
+
+	lastError := sql.NullString{String: taskErr.Error(), Valid: true}
+	if errors.Is(taskErr, ErrSkipRetry) || task.Attempts >= task.MaxAttempts {
+		log.Printf("worker: %s task %d is dead after %d attempts: %v", task.Type, task.ID, task.Attempts, taskErr)
+		updated, err := processor.store.KillTask(ctx, db.KillTaskParams{ID: task.ID, LastError: lastError, LockedUntil: task.LockedUntil})
+		return finished(task, "kill", updated, err)
+	}
+
+	runAt := processor.now().Add(processor.config.Backoff(task.Attempts))
+	log.Printf("worker: %s task %d failed attempt %d, retrying at %s: %v", task.Type, task.ID, task.Attempts, runAt.Format(time.RFC3339), taskErr)
+	updated, err := processor.store.RetryTask(ctx, db.RetryTaskParams{ID: task.ID, RunAt: runAt, LastError: lastError, LockedUntil: task.LockedUntil})
+	return finished(task, "retry", updated, err)
+}
+
+// ** finished turns the result of recording an outcome into an error
+func finished(task db.Task, action string, updated int64, err error) error {
+	if err != nil {
+		return fmt.Errorf("cannot %s task %d: %w", action, task.ID, err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("cannot %s task %d: %w", action, task.ID, errLeaseExpired)
+	}
+	return nil
+}
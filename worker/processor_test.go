@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/mail"
+)
+
+const testTask = "task:test"
+
+func newTestProcessor(t *testing.T, handler HandlerFunc) (*PGTaskProcessor, *fakeStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	store := newFakeStore(clock.Now)
+
+	processor := newPGTaskProcessor(store, mail.NewFakeSender(), "http://localhost:8080", ProcessorConfig{
+		Concurrency:  1,
+		PollInterval: time.Millisecond,
+		Lease:        time.Minute,
+	})
+	processor.now = clock.Now
+	processor.handlers[testTask] = handler
+	return processor, store, clock
+}
+
+func enqueueTestTask(t *testing.T, store *fakeStore, opts ...Option) db.Task {
+	task, err := NewPGTaskDistributor(store).(*PGTaskDistributor).distribute(context.Background(), testTask, "payload", append([]Option{ProcessAt(store.now())}, opts...))
+	require.NoError(t, err)
+	return task
+}
+
+func TestProcessNextCompletesTask(t *testing.T) {
+	var payload []byte
+	processor, store, _ := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		payload = task.Payload
+		return nil
+	})
+
+	processed, err := processor.processNext()
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	task := enqueueTestTask(t, store)
+	processed, err = processor.processNext()
+	require.NoError(t, err)
+	require.True(t, processed)
+	require.JSONEq(t, `"payload"`, string(payload))
+
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusDone, task.Status)
+	require.Equal(t, int32(1), task.Attempts)
+	require.True(t, task.CompletedAt.Valid)
+	require.False(t, task.LockedUntil.Valid)
+}
+
+func TestProcessNextRetriesWithBackoff(t *testing.T) {
+	processor, store, clock := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		return fmt.Errorf("attempt %d failed", task.Attempts)
+	})
+	task := enqueueTestTask(t, store, MaxAttempts(3))
+
+	for attempt := int32(1); attempt < 3; attempt++ {
+		processed, err := processor.processNext()
+		require.NoError(t, err)
+		require.True(t, processed)
+
+		task = store.task(task.ID)
+		require.Equal(t, db.TaskStatusPending, task.Status)
+		require.Equal(t, attempt, task.Attempts)
+		require.Equal(t, clock.Now().Add(DefaultBackoff(attempt)), task.RunAt)
+		require.Equal(t, fmt.Sprintf("attempt %d failed", attempt), task.LastError.String)
+
+		// ** the task is not due before its backoff is over
+		processed, err = processor.processNext()
+		require.NoError(t, err)
+		require.False(t, processed)
+		clock.Add(DefaultBackoff(attempt))
+	}
+
+	processed, err := processor.processNext()
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusDead, task.Status)
+	require.Equal(t, int32(3), task.Attempts)
+	require.Equal(t, "attempt 3 failed", task.LastError.String)
+}
+
+func TestProcessNextKillsTaskOnSkipRetry(t *testing.T) {
+	processor, store, _ := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		return fmt.Errorf("%w: bad payload", ErrSkipRetry)
+	})
+	task := enqueueTestTask(t, store)
+
+	_, err := processor.processNext()
+	require.NoError(t, err)
+
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusDead, task.Status)
+	require.Equal(t, int32(1), task.Attempts)
+	require.Equal(t, "skip retry: bad payload", task.LastError.String)
+}
+
+func TestProcessNextKillsTaskOfUnknownType(t *testing.T) {
+	processor, store, _ := newTestProcessor(t, nil)
+	task, err := store.CreateTask(context.Background(), db.CreateTaskParams{Type: "task:unknown", MaxAttempts: 5, RunAt: store.now()})
+	require.NoError(t, err)
+
+	_, err = processor.processNext()
+	require.NoError(t, err)
+
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusDead, task.Status)
+	require.Contains(t, task.LastError.String, "unknown task type")
+}
+
+func TestProcessNextRecoversPanic(t *testing.T) {
+	processor, store, _ := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		panic("boom")
+	})
+	task := enqueueTestTask(t, store)
+
+	_, err := processor.processNext()
+	require.NoError(t, err)
+
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusPending, task.Status)
+	require.Equal(t, "task panicked: boom", task.LastError.String)
+}
+
+func TestClaimExpiredLease(t *testing.T) {
+	processor, store, clock := newTestProcessor(t, func(ctx context.Context, task db.Task) error { return nil })
+	task := enqueueTestTask(t, store)
+
+	// ** a worker claims the task and crashes before finishing it
+	_, err := store.ClaimTask(context.Background(), time.Minute.Seconds())
+	require.NoError(t, err)
+
+	processed, err := processor.processNext()
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	clock.Add(time.Minute + time.Second)
+	processed, err = processor.processNext()
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusDone, task.Status)
+	require.Equal(t, int32(2), task.Attempts)
+}
+
+func TestFinishAfterLeaseExpired(t *testing.T) {
+	var store *fakeStore
+	var clock *fakeClock
+	processor, store, clock := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		// ** the task runs past its lease and another worker claims it meanwhile
+		clock.Add(time.Minute + time.Second)
+		_, err := store.ClaimTask(context.Background(), time.Minute.Seconds())
+		require.NoError(t, err)
+		return nil
+	})
+	task := enqueueTestTask(t, store)
+
+	processed, err := processor.processNext()
+	require.True(t, processed)
+	require.ErrorIs(t, err, errLeaseExpired)
+
+	// ** the outcome of the late run does not overwrite the run of the other worker
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusRunning, task.Status)
+	require.Equal(t, int32(2), task.Attempts)
+}
+
+func TestShutdownWaitsForRunningTask(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	processor, store, _ := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		close(started)
+		<-release
+		return nil
+	})
+	task := enqueueTestTask(t, store)
+
+	require.NoError(t, processor.Start())
+	<-started
+
+	var stopped atomic.Bool
+	done := make(chan error)
+	go func() {
+		err := processor.Shutdown(context.Background())
+		stopped.Store(true)
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	require.False(t, stopped.Load())
+
+	close(release)
+	require.NoError(t, <-done)
+	require.Equal(t, db.TaskStatusDone, store.task(task.ID).Status)
+}
+
+func TestShutdownCancelsRunningTaskAfterDeadline(t *testing.T) {
+	started := make(chan struct{})
+	processor, store, _ := newTestProcessor(t, func(ctx context.Context, task db.Task) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	task := enqueueTestTask(t, store)
+
+	require.NoError(t, processor.Start())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := processor.Shutdown(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// ** the cancelled task goes back to the queue for the next processor
+	task = store.task(task.ID)
+	require.Equal(t, db.TaskStatusPending, task.Status)
+	require.Equal(t, context.Canceled.Error(), task.LastError.String)
+}
+
+func TestStartRejectsInvalidConfig(t *testing.T) {
+	processor := newPGTaskProcessor(newFakeStore(time.Now), mail.NewFakeSender(), "", ProcessorConfig{})
+	require.Error(t, processor.Start())
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	require.Equal(t, 10*time.Second, DefaultBackoff(0))
+	require.Equal(t, 10*time.Second, DefaultBackoff(1))
+	require.Equal(t, 20*time.Second, DefaultBackoff(2))
+	require.Equal(t, 80*time.Second, DefaultBackoff(4))
+	require.Equal(t, time.Hour, DefaultBackoff(20))
+	require.Equal(t, time.Hour, DefaultBackoff(1000))
+}
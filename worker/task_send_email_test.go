@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/mail"
+)
+
+func newEmailTestProcessor(t *testing.T) (*PGTaskProcessor, *fakeStore, *mail.FakeSender) {
+	now := func() time.Time { return time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC) }
+	store := newFakeStore(now)
+	store.users["alice"] = db.User{Username: "alice", FullName: "Alice", Email: "alice@email.com"}
+
+	mailer := mail.NewFakeSender()
+	processor := newPGTaskProcessor(store, mailer, "http://localhost:8080", DefaultProcessorConfig)
+	processor.now = now
+	return processor, store, mailer
+}
+
+func newTask(t *testing.T, taskType string, payload interface{}) db.Task {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return db.Task{Type: taskType, Payload: data}
+}
+
+func TestProcessTaskSendVerifyEmail(t *testing.T) {
+	processor, store, mailer := newEmailTestProcessor(t)
+	store.verifyEmails[42] = db.VerifyEmail{
+		ID:        42,
+		Username:  "alice",
+		Email:     "alice@email.com",
+		ExpiredAt: time.Date(2023, time.March, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	task := newTask(t, TaskSendVerifyEmail, PayloadSendVerifyEmail{VerifyEmailID: 42, SecretCode: "a+b/c"})
+	require.NoError(t, processor.ProcessTaskSendVerifyEmail(context.Background(), task))
+
+	email, ok := mailer.Last()
+	require.True(t, ok)
+	require.Equal(t, []string{"alice@email.com"}, email.To)
+	require.Contains(t, email.Body, "Hello Alice,")
+	require.Contains(t, email.Body, "http://localhost:8080/users/verify_email?email_id=42&secret_code=a%2Bb%2Fc")
+	require.Contains(t, email.Body, "Thu, 02 Mar 2023 12:00:00 UTC")
+
+	// ** nothing is sent for a code that was used or has expired meanwhile
+	verifyEmail := store.verifyEmails[42]
+	verifyEmail.IsUsed = true
+	store.verifyEmails[42] = verifyEmail
+	require.NoError(t, processor.ProcessTaskSendVerifyEmail(context.Background(), task))
+	require.Len(t, mailer.Sent(), 1)
+
+	task = newTask(t, TaskSendVerifyEmail, PayloadSendVerifyEmail{VerifyEmailID: 43, SecretCode: "a+b/c"})
+	require.ErrorIs(t, processor.ProcessTaskSendVerifyEmail(context.Background(), task), ErrSkipRetry)
+
+	// ** tasks enqueued before codes were hashed carry no code, and there is none left to send
+	task = newTask(t, TaskSendVerifyEmail, PayloadSendVerifyEmail{VerifyEmailID: 42})
+	require.ErrorIs(t, processor.ProcessTaskSendVerifyEmail(context.Background(), task), ErrSkipRetry)
+
+	task = db.Task{Type: TaskSendVerifyEmail, Payload: []byte(`[]`)}
+	require.ErrorIs(t, processor.ProcessTaskSendVerifyEmail(context.Background(), task), ErrSkipRetry)
+}
+
+func TestProcessTaskSendPasswordReset(t *testing.T) {
+	processor, store, mailer := newEmailTestProcessor(t)
+	store.passwordResets[7] = db.PasswordReset{
+		ID:        7,
+		Username:  "alice",
+		ExpiredAt: time.Date(2023, time.March, 1, 12, 15, 0, 0, time.UTC),
+	}
+
+	task := newTask(t, TaskSendPasswordReset, PayloadSendPasswordReset{PasswordResetID: 7, SecretCode: "secret"})
+	require.NoError(t, processor.ProcessTaskSendPasswordReset(context.Background(), task))
+
+	email, ok := mailer.Last()
+	require.True(t, ok)
+	require.Equal(t, []string{"alice@email.com"}, email.To)
+	require.Contains(t, email.Body, "reset_id 7 and secret_code secret")
+
+	// ** a failing SMTP server is retried, an email that can never be sent is not
+	mailer.Err = errors.New("smtp down")
+	err := processor.ProcessTaskSendPasswordReset(context.Background(), task)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrSkipRetry)
+
+	mailer.Err = mail.ErrInvalidEmail
+	require.ErrorIs(t, processor.ProcessTaskSendPasswordReset(context.Background(), task), ErrSkipRetry)
+
+	reset := store.passwordResets[7]
+	reset.ExpiredAt = processor.now()
+	store.passwordResets[7] = reset
+	mailer.Err = nil
+	require.NoError(t, processor.ProcessTaskSendPasswordReset(context.Background(), task))
+	require.Len(t, mailer.Sent(), 1)
+}
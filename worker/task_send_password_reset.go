@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/mail"
+)
+
+// ** TaskSendPasswordReset sends the email carrying a password reset code
+const TaskSendPasswordReset = "task:send_password_reset"
+
+// ** PayloadSendPasswordReset refers to the reset and carries its secret code,
+// **  of which the database only keeps the hash. Like PayloadSendVerifyEmail, it is cleared
+// **  once the task completes or dies.
+type PayloadSendPasswordReset struct {
+	PasswordResetID int64  `json:"password_reset_id"`
+	SecretCode      string `json:"secret_code"`
+}
+
+func (distributor *PGTaskDistributor) DistributeTaskSendPasswordReset(ctx context.Context, payload *PayloadSendPasswordReset, opts ...Option) (db.Task, error) {
+	return distributor.distribute(ctx, TaskSendPasswordReset, payload, opts)
+}
+
+// ** ProcessTaskSendPasswordReset sends the reset code, unless it was used or expired meanwhile
+func (processor *PGTaskProcessor) ProcessTaskSendPasswordReset(ctx context.Context, task db.Task) error {
+	var payload PayloadSendPasswordReset
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", ErrSkipRetry, err)
+	}
+	if payload.SecretCode == "" {
+		return fmt.Errorf("%w: password reset %d: payload carries no code", ErrSkipRetry, payload.PasswordResetID)
+	}
+
+	reset, err := processor.store.GetPasswordReset(ctx, payload.PasswordResetID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("%w: password reset %d not found", ErrSkipRetry, payload.PasswordResetID)
+		}
+		return err
+	}
+	if reset.IsUsed || !processor.now().Before(reset.ExpiredAt) {
+		return nil
+	}
+
+	user, err := processor.store.GetUser(ctx, reset.Username)
+	if err != nil {
+		return err
+	}
+	return sendEmail(processor.mailer, PasswordResetMessage(processor.publicURL, user, reset, payload.SecretCode))
+}
+
+// ** PasswordResetMessage is the email with the code that lets a user choose a new password
+func PasswordResetMessage(publicURL string, user db.User, reset db.PasswordReset, secretCode string) mail.Email {
+	return mail.Email{
+		To:      []string{user.Email},
+		Subject: "Reset your Simple Bank password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Somebody asked to reset the password of your account. To choose a new password,\n"+
+			"send reset_id %d and secret_code %s to %s/users/password_reset/confirm.\n\n"+
+			"The code expires at %s. If you did not ask for it, you can ignore this email.\n",
+			user.FullName, reset.ID, secretCode, publicURL, reset.ExpiredAt.UTC().Format(time.RFC1123)),
+	}
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	db "github.com/techschool/simplebank/db/sqlc"
+	"github.com/techschool/simplebank/mail"
+)
+
+// ** TaskSendVerifyEmail sends the email that verifies the address of a new user
+const TaskSendVerifyEmail = "task:send_verify_email"
+
+// ** PayloadSendVerifyEmail refers to the verification and carries its secret code,
+// **  of which the database only keeps the hash. CompleteTask and KillTask clear the payload,
+// **  so the code stays in the task queue only until the email is sent or given up on.
+type PayloadSendVerifyEmail struct {
+	VerifyEmailID int64  `json:"verify_email_id"`
+	SecretCode    string `json:"secret_code"`
+}
+
+func (distributor *PGTaskDistributor) DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...Option) (db.Task, error) {
+	return distributor.distribute(ctx, TaskSendVerifyEmail, payload, opts)
+}
+
+// ** ProcessTaskSendVerifyEmail sends the verification email, unless the code was used or expired meanwhile
+func (processor *PGTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task db.Task) error {
+	var payload PayloadSendVerifyEmail
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", ErrSkipRetry, err)
+	}
+	if payload.SecretCode == "" {
+		return fmt.Errorf("%w: email verification %d: payload carries no code", ErrSkipRetry, payload.VerifyEmailID)
+	}
+
+	verifyEmail, err := processor.store.GetVerifyEmail(ctx, payload.VerifyEmailID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("%w: email verification %d not found", ErrSkipRetry, payload.VerifyEmailID)
+		}
+		return err
+	}
+	if verifyEmail.IsUsed || !processor.now().Before(verifyEmail.ExpiredAt) {
+		return nil
+	}
+
+	user, err := processor.store.GetUser(ctx, verifyEmail.Username)
+	if err != nil {
+		return err
+	}
+	return sendEmail(processor.mailer, VerifyEmailMessage(processor.publicURL, user, verifyEmail, payload.SecretCode))
+}
+
+// ** VerifyEmailMessage is the email with the link that verifies the address of a new user
+func VerifyEmailMessage(publicURL string, user db.User, verifyEmail db.VerifyEmail, secretCode string) mail.Email {
+	link := fmt.Sprintf("%s/users/verify_email?%s", publicURL, url.Values{
+		"email_id":    {fmt.Sprint(verifyEmail.ID)},
+		"secret_code": {secretCode},
+	}.Encode())
+
+	return mail.Email{
+		To:      []string{verifyEmail.Email},
+		Subject: "Welcome to Simple Bank",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Thank you for registering with us. Please verify your email address by opening this link:\n\n"+
+			"%s\n\n"+
+			"The link expires at %s.\n", user.FullName, link, verifyEmail.ExpiredAt.UTC().Format(time.RFC1123)),
+	}
+}
+
+// ** sendEmail sends the email; emails that can never be sent are not retried
+func sendEmail(mailer mail.Sender, email mail.Email) error {
+	err := mailer.SendEmail(email)
+	if errors.Is(err, mail.ErrInvalidEmail) {
+		return fmt.Errorf("%w: %v", ErrSkipRetry, err)
+	}
+	return err
+}